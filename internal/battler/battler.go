@@ -2,15 +2,19 @@ package battler
 
 import (
 	"fmt"
+	"maps"
+	"slices"
 	"sync"
 
 	"github.com/45uperman/dndbattlercli/internal/battler/combatant"
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
 	"github.com/45uperman/dndbattlercli/internal/battler/spellbook"
 )
 
 type Battler struct {
-	Combatants map[string]combatant.Combatant
+	Combatants map[string]*combatant.Combatant
 	Spells     map[string]spellbook.Spell
+	Initiative *Initiative
 	MU         *sync.RWMutex
 }
 
@@ -70,7 +74,7 @@ func (b Battler) DisplayNames() {
 func (b Battler) AddCombatant(c combatant.Combatant) {
 	b.MU.Lock()
 	defer b.MU.Unlock()
-	b.Combatants[c.StatBlock.Name] = c
+	b.Combatants[c.StatBlock.Name] = &c
 }
 
 func (b Battler) AddSpell(s spellbook.Spell) {
@@ -83,7 +87,10 @@ func (b Battler) GetCombatant(combatantName string) (*combatant.Combatant, bool)
 	b.MU.RLock()
 	defer b.MU.RUnlock()
 	c, ok := b.Combatants[combatantName]
-	return &c, ok
+	if !ok {
+		return &combatant.Combatant{}, false
+	}
+	return c, ok
 }
 
 func (b Battler) GetSpell(spellName string) (*spellbook.Spell, bool) {
@@ -93,10 +100,100 @@ func (b Battler) GetSpell(spellName string) (*spellbook.Spell, bool) {
 	return &s, ok
 }
 
+// EndBrokenConcentration removes every condition linked to a spell its
+// source is no longer concentrating on, and returns them.
+func (b Battler) EndBrokenConcentration() []EndedCondition {
+	b.MU.Lock()
+	defer b.MU.Unlock()
+
+	var ended []EndedCondition
+	for _, name := range slices.Sorted(maps.Keys(b.Combatants)) {
+		c := b.Combatants[name]
+		for _, condition := range slices.Clone(c.Conditions) {
+			if condition.Spell == "" {
+				continue
+			}
+			source, ok := b.Combatants[condition.Source]
+			if ok && source.Concentration == condition.Spell {
+				continue
+			}
+			c.RemoveCondition(condition.Name)
+			ended = append(ended, EndedCondition{Combatant: name, Condition: condition})
+		}
+	}
+
+	return ended
+}
+
+// Spawned is one combatant made by Spawn, along with its hit point roll if
+// it had one.
+type Spawned struct {
+	Name   string
+	HP     int
+	Rolled bool
+	Roll   dice.RollResult
+}
+
+// Spawn adds count copies of a combatant, numbered after the lowest numbers
+// that aren't taken yet, so spawning 3 goblins makes goblin 1, goblin 2 and
+// goblin 3. Their max hit points come from the original, unless hitPoints is
+// "roll" to roll them from its hit dice or "average" to use their average.
+func (b Battler) Spawn(template string, count int, hitPoints string) ([]Spawned, error) {
+	b.MU.Lock()
+	defer b.MU.Unlock()
+
+	c, ok := b.Combatants[template]
+	if !ok {
+		return nil, fmt.Errorf("could not find combatant: %s", template)
+	}
+	if c.Template != "" {
+		return nil, fmt.Errorf("%s was spawned from %s - spawn more of %s instead", template, c.Template, c.Template)
+	}
+	if count < 1 {
+		return nil, fmt.Errorf("can't spawn %d of %s", count, template)
+	}
+	if hitPoints != "" && c.StatBlock.HitDice == "" {
+		return nil, fmt.Errorf("%s doesn't have any hit dice", template)
+	}
+
+	var spawned []Spawned
+	number := 1
+	for range count {
+		for b.Combatants[fmt.Sprintf("%s %d", template, number)] != nil {
+			number++
+		}
+		name := fmt.Sprintf("%s %d", template, number)
+
+		copied, err := c.Spawn(name)
+		if err != nil {
+			return spawned, err
+		}
+
+		report := Spawned{Name: name}
+		switch hitPoints {
+		case "roll":
+			report.Roll, err = copied.RollHP()
+			report.Rolled = true
+		case "average":
+			_, err = copied.AverageHP()
+		}
+		if err != nil {
+			return spawned, err
+		}
+		report.HP = copied.StatBlock.HP["max"]
+
+		b.Combatants[name] = &copied
+		spawned = append(spawned, report)
+	}
+
+	return spawned, nil
+}
+
 func NewBattler() Battler {
 	b := Battler{
-		Combatants: map[string]combatant.Combatant{},
+		Combatants: map[string]*combatant.Combatant{},
 		Spells:     map[string]spellbook.Spell{},
+		Initiative: &Initiative{},
 		MU:         &sync.RWMutex{},
 	}
 	return b
@@ -0,0 +1,382 @@
+package combatant
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+)
+
+// ActionReport is how an action taken against a target went.
+type ActionReport struct {
+	Target string
+	// Attacked means the action had an attack roll
+	Attacked bool
+	Hit      bool
+	Critical bool
+	Fumble   bool
+	// Save is only set if the action made the target roll one
+	Save *SaveReport
+	// Damage is the damage the target actually took, by damage type
+	Damage map[string]int
+	// Healing counts both healing and temporary hit points
+	Healing int
+}
+
+// rollAction rolls everything for an action without a target, and leaves
+// it up to whoever's running the battler to work out what happened.
+func (c *Combatant) rollAction(action Action) error {
+	var critical bool
+	if action.AttackRoll.Present {
+		modifiers := c.AttackModifiers()
+		attackRoll := dice.D20.Roll(modifiers.Advantage, modifiers.Disadvantage).Add(action.AttackRoll.Modifier)
+		fmt.Printf(
+			"Attack roll:\n - %d to hit (%s)\n",
+			attackRoll.Total,
+			attackRoll.Breakdown(),
+		)
+		for _, reason := range modifiers.Reasons {
+			fmt.Printf(" - Rolled with %s\n", reason)
+		}
+
+		if attackRoll.Natural1() {
+			fmt.Println(" - Natural 1, critical miss!")
+			return nil
+		}
+
+		critical = isCritical(attackRoll, action.AttackRoll.CritRange)
+		if critical {
+			fmt.Printf(" - Natural %d, critical hit! Damage dice are doubled\n", attackRoll.Natural)
+		}
+	}
+
+	if action.SavingThrow.Present {
+		fmt.Printf(
+			"\nRequires DC %d %s saving throw!\n",
+			action.SavingThrow.DC,
+			strings.ToUpper(action.SavingThrow.Ability),
+		)
+		if action.SavingThrow.HalfEffectOnSuccess {
+			fmt.Println(" - Half effect on a success")
+		}
+		for _, condition := range action.SavingThrow.Conditions {
+			fmt.Printf(" - %s on a failure\n", capitalize(condition.Name))
+		}
+	}
+
+	fmt.Println("\nEffects:")
+	err := printEffectRolls(action.Effects, critical)
+	if err != nil {
+		return err
+	}
+
+	if len(action.SavingThrow.Effects) != 0 {
+		fmt.Println("\nOn a failed save:")
+		return printEffectRolls(action.SavingThrow.Effects, critical)
+	}
+
+	return nil
+}
+
+// rollOrder sorts the names of whatever's about to be rolled for, so a seeded
+// roller rolls them in the same order every time.
+func rollOrder[V any](m map[string]V) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+func printEffectRolls(effects map[string]Effect, critical bool) error {
+	for _, name := range rollOrder(effects) {
+		effect := effects[name]
+		result, err := rollEffectDice(effect.Roll, critical)
+		if err != nil {
+			return err
+		}
+		fmt.Printf(" - %s: %d %s (%s)\n", name, result.Total, effect.Type, result.Breakdown())
+	}
+	return nil
+}
+
+// resolveAction takes an action against a target. An attack roll has to hit
+// for anything else to happen. A saving throw on an action with an attack
+// roll is rolled after the hit, and on an action without one, it decides
+// whether the target takes the action's effects too.
+func (c *Combatant) resolveAction(action Action, target *Combatant) (ActionReport, error) {
+	report := ActionReport{Target: target.StatBlock.Name, Damage: map[string]int{}}
+
+	if action.AttackRoll.Present {
+		modifiers := c.AttackModifiers().combine(target.DefenseModifiers())
+		attackRoll := dice.D20.Roll(modifiers.Advantage, modifiers.Disadvantage).Add(action.AttackRoll.Modifier)
+		fmt.Printf("Attack roll against %s (AC %d): %s\n", target.StatBlock.Name, target.StatBlock.AC, attackRoll)
+		for _, reason := range modifiers.Reasons {
+			fmt.Printf(" - Rolled with %s\n", reason)
+		}
+
+		attack := target.Attack(attackRoll, action.AttackRoll.CritRange)
+		report.Attacked = true
+		report.Hit, report.Critical, report.Fumble = attack.Hit, attack.Critical, attack.Fumble
+		switch {
+		case attack.Fumble:
+			fmt.Printf("Natural 1, missed %s!    FUMBLE!\n", target.StatBlock.Name)
+			return report, nil
+		case !attack.Hit:
+			fmt.Printf("Missed %s!    MISS!\n", target.StatBlock.Name)
+			return report, nil
+		case attack.Critical:
+			fmt.Printf("Critically hit %s! Damage dice are doubled    CRIT!\n", target.StatBlock.Name)
+		default:
+			fmt.Printf("Hit %s!    HIT!\n", target.StatBlock.Name)
+		}
+	}
+
+	if !action.SavingThrow.Present {
+		fmt.Println("\nEffects:")
+		return report, applyEffects(action.Effects, target, report.Critical, false, &report)
+	}
+
+	if action.AttackRoll.Present && len(action.Effects) != 0 {
+		fmt.Println("\nEffects:")
+		err := applyEffects(action.Effects, target, report.Critical, false, &report)
+		if err != nil {
+			return report, err
+		}
+		fmt.Printf("\n")
+	}
+
+	save, err := c.forceSave(action, target)
+	if err != nil {
+		return report, err
+	}
+	report.Save = &save
+
+	// Without an attack roll, the save decides what happens with all of the
+	// action's effects
+	saveEffects := action.SavingThrow.Effects
+	if !action.AttackRoll.Present {
+		saveEffects = maps.Clone(action.Effects)
+		if saveEffects == nil {
+			saveEffects = map[string]Effect{}
+		}
+		maps.Copy(saveEffects, action.SavingThrow.Effects)
+	}
+
+	half := save.Success && action.SavingThrow.HalfEffectOnSuccess
+	if len(saveEffects) != 0 && (!save.Success || half) {
+		if half {
+			fmt.Println("\nEffects (halved):")
+		} else {
+			fmt.Println("\nEffects:")
+		}
+		err = applyEffects(saveEffects, target, report.Critical, half, &report)
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if !save.Success {
+		c.applySaveConditions(action, target)
+	}
+
+	return report, nil
+}
+
+// applySaveConditions gives the target every condition that comes with
+// failing the action's saving throw, with the combatant as their source.
+func (c *Combatant) applySaveConditions(action Action, target *Combatant) {
+	for _, condition := range action.SavingThrow.Conditions {
+		condition.Source = c.StatBlock.Name
+		lost, err := target.ApplyCondition(condition)
+		if err != nil {
+			fmt.Printf(" - %s\n", err)
+			continue
+		}
+		fmt.Printf(" - %s is now %s\n", target.StatBlock.Name, condition)
+		if lost != "" {
+			fmt.Printf(" - %s lost concentration on %s!\n", target.StatBlock.Name, lost)
+		}
+	}
+}
+
+// forceSave has the target roll the action's saving throw, and displays how
+// it went.
+func (c *Combatant) forceSave(action Action, target *Combatant) (SaveReport, error) {
+	save, err := target.Save(action.SavingThrow.DC, action.SavingThrow.Ability, false, false)
+	if err != nil {
+		return SaveReport{}, err
+	}
+
+	fmt.Printf("DC %d %s saving throw:", action.SavingThrow.DC, strings.ToUpper(action.SavingThrow.Ability))
+	if !save.Modifiers.AutoFail {
+		fmt.Printf(" %s", save.Roll)
+	}
+	fmt.Printf("\n")
+	for _, reason := range save.Modifiers.Reasons {
+		fmt.Printf(" - Rolled with %s\n", reason)
+	}
+	if save.LegendaryResistance {
+		fmt.Printf(
+			" - %s used a legendary resistance to succeed instead (%d left)\n",
+			target.StatBlock.Name,
+			target.LegendaryResistancesLeft(),
+		)
+	}
+
+	if save.Success {
+		fmt.Printf("%s saved!    SAVED!\n", target.StatBlock.Name)
+	} else {
+		fmt.Printf("%s failed the save!    FAILED!\n", target.StatBlock.Name)
+	}
+
+	return save, nil
+}
+
+// applyEffects rolls each effect and applies it to the target, or half of
+// it, adding what happened to report.
+func applyEffects(effects map[string]Effect, target *Combatant, critical, half bool, report *ActionReport) error {
+	for _, name := range rollOrder(effects) {
+		effect := effects[name]
+		result, err := rollEffectDice(effect.Roll, critical)
+		if err != nil {
+			return err
+		}
+		breakdown := result.Breakdown()
+		if half {
+			result.Total /= 2
+			breakdown = fmt.Sprintf("(%s)/2", breakdown)
+		}
+
+		var effectReport EffectReport
+		switch effect.Type {
+		case "healing":
+			effectReport = target.HealHP(result.Total)
+			report.Healing += effectReport.TrueEffect
+			fmt.Printf(" - %s: healed %d hit points (%s)\n", name, effectReport.TrueEffect, breakdown)
+		case "temp_hp":
+			effectReport = target.GainTempHP(result.Total)
+			report.Healing += effectReport.TrueEffect
+			fmt.Printf(" - %s: gained %d temporary hit points (%s)\n", name, effectReport.TrueEffect, breakdown)
+			if effectReport.KeptTemp {
+				fmt.Printf(" - Target kept the %d temporary hit points it already had!\n", effectReport.TrueEffect)
+			}
+			continue
+		default:
+			if critical {
+				effectReport = target.TakeCritDMG(result.Total, effect.Type)
+			} else {
+				effectReport = target.TakeDMG(result.Total, effect.Type)
+			}
+			report.Damage[effect.Type] += effectReport.TrueEffect
+			fmt.Printf(" - %s: took %d %s damage (%s)\n", name, effectReport.TrueEffect, effect.Type, breakdown)
+		}
+
+		for _, note := range effectReport.Notes(effect.Type) {
+			fmt.Printf(" - %s\n", note)
+		}
+	}
+
+	return nil
+}
+
+func rollEffectDice(roll string, critical bool) (dice.RollResult, error) {
+	d, err := dice.ReadDiceExpression(roll)
+	if err != nil {
+		return dice.RollResult{}, err
+	}
+	if critical {
+		d = d.Critical()
+	}
+	return d.Roll(false, false), nil
+}
+
+type actionStep struct {
+	name   string
+	action Action
+}
+
+// multiattackSteps returns the actions a multiattack is made of, or just
+// the action itself if it isn't one.
+func (c Combatant) multiattackSteps(actionName string, action Action, force bool) ([]actionStep, error) {
+	if len(action.Multiattack) == 0 {
+		return []actionStep{{name: actionName, action: action}}, nil
+	}
+
+	// Each step spends its action, so an action listed twice can run out
+	// partway through
+	spending := map[string]Action{}
+	steps := make([]actionStep, len(action.Multiattack))
+	for i, name := range action.Multiattack {
+		name = strings.ReplaceAll(name, " ", "_")
+		step, ok := c.StatBlock.Actions[name]
+		if !ok {
+			return nil, fmt.Errorf("%s is part of %s, but it isn't one of %s's actions", name, actionName, c.StatBlock.Name)
+		}
+		if len(step.Multiattack) != 0 {
+			return nil, fmt.Errorf("%s can't be part of %s, since it's a multiattack too", name, actionName)
+		}
+		if spent, ok := spending[name]; ok {
+			step = spent
+		}
+		if step.Spent() && !force {
+			return nil, fmt.Errorf("%s can't use %s as part of %s right now%s - use the force flag to take it anyway", c.StatBlock.Name, name, actionName, step.usage())
+		}
+		spending[name] = step.spend()
+		steps[i] = actionStep{name: name, action: step}
+	}
+	return steps, nil
+}
+
+// printActionSummary adds up how every target did across a multiattack.
+func printActionSummary(reports []ActionReport) {
+	var order []string
+	totals := map[string][]ActionReport{}
+	for _, report := range reports {
+		if _, ok := totals[report.Target]; !ok {
+			order = append(order, report.Target)
+		}
+		totals[report.Target] = append(totals[report.Target], report)
+	}
+
+	fmt.Println("Total:")
+	for _, target := range order {
+		var hits, attacks, damage, healing int
+		byType := map[string]int{}
+		for _, report := range totals[target] {
+			if report.Hit {
+				hits++
+			}
+			if report.Attacked {
+				attacks++
+			}
+			for dmgType, amount := range report.Damage {
+				byType[dmgType] += amount
+				damage += amount
+			}
+			healing += report.Healing
+		}
+
+		text := fmt.Sprintf(" - %s: ", target)
+		if attacks > 0 {
+			text += fmt.Sprintf("%d/%d hits, ", hits, attacks)
+		}
+		switch len(byType) {
+		case 1:
+			for dmgType := range byType {
+				text += fmt.Sprintf("%d %s damage", damage, dmgType)
+			}
+		default:
+			text += fmt.Sprintf("%d damage", damage)
+			var parts []string
+			for _, dmgType := range slices.Sorted(maps.Keys(byType)) {
+				parts = append(parts, fmt.Sprintf("%d %s", byType[dmgType], dmgType))
+			}
+			if len(parts) != 0 {
+				text += fmt.Sprintf(" (%s)", strings.Join(parts, ", "))
+			}
+		}
+		if healing > 0 {
+			text += fmt.Sprintf(", %d healing", healing)
+		}
+		fmt.Println(text)
+	}
+}
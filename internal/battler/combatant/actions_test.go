@@ -0,0 +1,42 @@
+package combatant
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/45uperman/dndbattlercli/internal/golden"
+)
+
+func loadCombatant(t *testing.T, file string) *Combatant {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", file))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var c Combatant
+	if err := json.Unmarshal(data, &c); err != nil {
+		t.Fatal(err)
+	}
+	return &c
+}
+
+func TestDoActionSeeded(t *testing.T) {
+	golden.Seed(t, 3)
+
+	ogre := loadCombatant(t, "ogre.json")
+	knight := loadCombatant(t, "knight.json")
+
+	got := golden.Capture(t, func() {
+		for _, name := range []string{"multiattack", "bellow"} {
+			if _, err := ogre.DoAction(name, "action", false, []*Combatant{knight}); err != nil {
+				t.Errorf("%s: %s", name, err)
+			}
+		}
+		fmt.Printf("knight: %d/%d HP, conditions %v\n", knight.StatBlock.HP["current"], knight.StatBlock.HP["max"], knight.Conditions)
+	})
+	golden.Check(t, "do_action", got)
+}
@@ -0,0 +1,103 @@
+package combatant
+
+import (
+	"slices"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+)
+
+// CheckModifier is what gets added to the combatant's checks with a skill,
+// or with an ability like 'str' for a plain strength check.
+func (c Combatant) CheckModifier(skillOrAbility string) (int, error) {
+	if slices.Contains(Abilities, skillOrAbility) {
+		score, err := c.AbilityScore(skillOrAbility)
+		return AbilityModifier(score), err
+	}
+	return c.SkillModifier(skillOrAbility)
+}
+
+// CheckModifiers is what the combatant's conditions do to its ability
+// checks.
+func (c Combatant) CheckModifiers() RollModifiers {
+	var modifiers RollModifiers
+	for _, condition := range c.Conditions {
+		modifiers.add(conditionEffects[condition.Name].checks, condition.Name)
+	}
+	return modifiers
+}
+
+type CheckReport struct {
+	// Success is only meaningful when the check was made against a DC
+	Success   bool
+	Roll      dice.RollResult
+	Modifiers RollModifiers
+}
+
+// Check makes an ability check with a skill or an ability, against the
+// provided DC unless it's nil.
+func (c Combatant) Check(skillOrAbility string, dc *int, advantage, disadvantage bool) (CheckReport, error) {
+	mod, err := c.CheckModifier(skillOrAbility)
+	if err != nil {
+		return CheckReport{}, err
+	}
+
+	report := CheckReport{Modifiers: c.CheckModifiers()}
+	report.Roll = dice.D20.Roll(
+		advantage || report.Modifiers.Advantage,
+		disadvantage || report.Modifiers.Disadvantage,
+	).Add(mod)
+	report.Success = dc != nil && report.Roll.Total >= *dc
+
+	return report, nil
+}
+
+// Contestant is one side of a contest, like the grappler or the creature
+// trying to escape.
+type Contestant struct {
+	Combatant    *Combatant
+	Skill        string
+	Advantage    bool
+	Disadvantage bool
+}
+
+type ContestReport struct {
+	Checks [2]CheckReport
+	// Winner is 0 or 1 for whichever contestant won, and -1 for a tie, which
+	// leaves things the way they were before the contest
+	Winner int
+}
+
+// Contest has two combatants make opposed checks, and the higher total wins.
+func Contest(first, second Contestant) (ContestReport, error) {
+	var report ContestReport
+	for i, contestant := range []Contestant{first, second} {
+		check, err := contestant.Combatant.Check(contestant.Skill, nil, contestant.Advantage, contestant.Disadvantage)
+		if err != nil {
+			return ContestReport{}, err
+		}
+		report.Checks[i] = check
+	}
+
+	switch {
+	case report.Checks[0].Roll.Total > report.Checks[1].Roll.Total:
+		report.Winner = 0
+	case report.Checks[0].Roll.Total < report.Checks[1].Roll.Total:
+		report.Winner = 1
+	default:
+		report.Winner = -1
+	}
+
+	return report, nil
+}
+
+// ResistWith picks which skill the combatant uses against someone else's
+// athletics check, like a grapple or a shove, which is whichever of
+// athletics or acrobatics it's better at.
+func (c Combatant) ResistWith() string {
+	athletics, _ := c.SkillModifier("athletics")
+	acrobatics, _ := c.SkillModifier("acrobatics")
+	if acrobatics > athletics {
+		return "acrobatics"
+	}
+	return "athletics"
+}
@@ -10,13 +10,17 @@ import (
 
 type Combatant struct {
 	StatBlock struct {
-		FileName  string         `json:"file_name"`
-		Name      string         `json:"name"`
-		Type      string         `json:"type"`
-		HP        map[string]int `json:"hp"`
-		AC        int            `json:"ac"`
-		Speed     int            `json:"speed"`
-		Abilities struct {
+		FileName        string         `json:"file_name"`
+		Name            string         `json:"name"`
+		Type            string         `json:"type"`
+		HP              map[string]int `json:"hp"`
+		HitDice         string         `json:"hit_dice"`
+		AC              int            `json:"ac"`
+		Speed           int            `json:"speed"`
+		InitiativeBonus int            `json:"initiative_bonus"`
+		PlayerCharacter bool           `json:"player_character"`
+		Tags            []string       `json:"tags"`
+		Abilities       struct {
 			STR int `json:"str"`
 			DEX int `json:"dex"`
 			CON int `json:"con"`
@@ -24,8 +28,15 @@ type Combatant struct {
 			WIS int `json:"wis"`
 			CHA int `json:"cha"`
 		} `json:"abilities"`
+		Level           int     `json:"level"`
+		ChallengeRating float64 `json:"challenge_rating"`
+		// Saves and skills listed with a modifier use it as is, and anything
+		// listed as a proficiency has its modifier worked out instead
 		Saves               map[string]int `json:"saves"`
+		SaveProficiencies   []string       `json:"save_proficiencies"`
 		Skills              map[string]int `json:"skills"`
+		SkillProficiencies  []string       `json:"skill_proficiencies"`
+		SkillExpertise      []string       `json:"skill_expertise"`
 		Vulnerabilities     []string       `json:"vulnerabilities"`
 		Resistances         []string       `json:"resistances"`
 		Immunities          []string       `json:"immunities"`
@@ -39,10 +50,34 @@ type Combatant struct {
 		Actions      map[string]Action `json:"actions"`
 		BonusActions map[string]Action `json:"bonus_actions"`
 		Reactions    map[string]Action `json:"reactions"`
+		// Legendary actions come out of a pool that refills at the start of
+		// the combatant's turn
+		LegendaryActions     map[string]Action `json:"legendary_actions"`
+		LegendaryActionPool  Uses              `json:"legendary_action_pool"`
+		LegendaryResistances Uses              `json:"legendary_resistances"`
+		LairActions          map[string]Action `json:"lair_actions"`
 	} `json:"statblock"`
+	Conditions    []Condition `json:"conditions,omitempty"`
+	Concentration string      `json:"concentration,omitempty"`
+	DeathSaves    *DeathSaves `json:"death_saves,omitempty"`
+	// HitDiceSpent counts the hit dice spent on short rests, by what size
+	// of die they are
+	HitDiceSpent map[int]int `json:"hit_dice_spent,omitempty"`
+	// Template is the combatant this one was spawned from, if it was
+	Template string `json:"template,omitempty"`
 }
 
-func (c Combatant) TakeDMG(dmg int, dmgType string) EffectReport {
+func (c *Combatant) TakeDMG(dmg int, dmgType string) EffectReport {
+	return c.takeDMG(dmg, dmgType, false)
+}
+
+// TakeCritDMG is TakeDMG for damage from a critical hit, which counts as two
+// failed death saves against a player character at 0 hit points.
+func (c *Combatant) TakeCritDMG(dmg int, dmgType string) EffectReport {
+	return c.takeDMG(dmg, dmgType, true)
+}
+
+func (c *Combatant) takeDMG(dmg int, dmgType string, critical bool) EffectReport {
 	report := EffectReport{}
 
 	if c.StatBlock.HP["current"] <= 0 {
@@ -63,22 +98,62 @@ func (c Combatant) TakeDMG(dmg int, dmgType string) EffectReport {
 		report.WasResistant = true
 	}
 
-	c.StatBlock.HP["current"] -= dmg
+	if c.StatBlock.HP["temp"] > 0 {
+		report.TempAbsorbed = min(dmg, c.StatBlock.HP["temp"])
+		c.StatBlock.HP["temp"] -= report.TempAbsorbed
+	}
+
+	// Whatever's left over after dropping to 0 hit points
+	overflow := dmg - report.TempAbsorbed - c.StatBlock.HP["current"]
+
+	c.StatBlock.HP["current"] -= dmg - report.TempAbsorbed
 	if c.StatBlock.HP["current"] <= 0 {
 		c.StatBlock.HP["current"] = 0
 		report.DroppedToZero = true
 	}
 
+	if c.StatBlock.PlayerCharacter {
+		switch {
+		case report.WasAtZero:
+			// Loaded at 0 hit points without any death saves to go with it
+			if c.DeathSaves == nil {
+				c.fallUnconscious()
+			}
+			if overflow > 0 {
+				c.damageAtZero(overflow, critical, &report)
+			}
+		case !report.DroppedToZero:
+		case overflow >= c.StatBlock.HP["max"]:
+			c.DeathSaves = &DeathSaves{Dead: true}
+			report.MassiveDamage = true
+			report.Died = true
+		default:
+			c.fallUnconscious()
+			report.StartedDying = true
+		}
+	}
+
 	report.TrueEffect = dmg
+	c.checkConcentration(dmg, &report)
 
 	return report
 }
 
-func (c Combatant) HealHP(hp int) EffectReport {
+func (c *Combatant) HealHP(hp int) EffectReport {
 	report := EffectReport{TrueEffect: hp}
 
+	if c.IsDead() {
+		report.WasDead = true
+		report.TrueEffect = 0
+		return report
+	}
+
 	if c.StatBlock.HP["current"] == 0 && hp > 0 {
 		report.BackAboveZero = true
+		if c.DeathSaves != nil {
+			c.DeathSaves = nil
+			c.RemoveCondition("unconscious")
+		}
 	}
 
 	c.StatBlock.HP["current"] += hp
@@ -89,6 +164,25 @@ func (c Combatant) HealHP(hp int) EffectReport {
 	return report
 }
 
+// GainTempHP gives the combatant temporary hit points. They don't stack, so
+// the combatant keeps whichever is higher out of the old and the new ones.
+func (c *Combatant) GainTempHP(hp int) EffectReport {
+	report := EffectReport{}
+
+	if c.StatBlock.HP == nil {
+		c.StatBlock.HP = map[string]int{}
+	}
+
+	if hp <= c.StatBlock.HP["temp"] {
+		report.KeptTemp = true
+	} else {
+		c.StatBlock.HP["temp"] = hp
+	}
+	report.TrueEffect = c.StatBlock.HP["temp"]
+
+	return report
+}
+
 func (c Combatant) Hits(attackRoll int) bool {
 	if attackRoll >= c.StatBlock.AC {
 		return true
@@ -97,82 +191,159 @@ func (c Combatant) Hits(attackRoll int) bool {
 	}
 }
 
-func (c Combatant) DoAction(actionName, actionType string) error {
-	var action Action
-	var ok bool
+// Attack resolves an attack roll against the combatant. A natural 1 always
+// misses, and a natural roll inside the crit range always hits and crits.
+func (c Combatant) Attack(attackRoll dice.RollResult, critRange int) AttackReport {
+	report := AttackReport{}
+
+	switch {
+	case attackRoll.Natural1():
+		report.Fumble = true
+	case isCritical(attackRoll, critRange):
+		report.Hit = true
+		report.Critical = true
+	default:
+		report.Hit = c.Hits(attackRoll.Total)
+	}
+
+	return report
+}
+
+func isCritical(attackRoll dice.RollResult, critRange int) bool {
+	if critRange == 0 {
+		critRange = 20
+	}
+	return attackRoll.Natural != 0 && attackRoll.Natural >= critRange
+}
+
+// InitiativeModifier is what gets added to the combatant's initiative roll.
+func (c Combatant) InitiativeModifier() int {
+	return AbilityModifier(c.StatBlock.Abilities.DEX) + c.StatBlock.InitiativeBonus
+}
+
+// AbilityModifier works out the modifier for an ability score, rounding down
+// so an 8 or a 9 gives -1.
+func AbilityModifier(score int) int {
+	if score < 10 {
+		return (score - 11) / 2
+	}
+	return (score - 10) / 2
+}
+
+// DoAction takes one of the combatant's actions. Actions that are out of
+// uses or waiting on a recharge get refused unless force is set. With a
+// target, the action's attack roll, effects, and saving throw all get
+// resolved against it, otherwise they're just rolled and displayed. A
+// multiattack takes each of its actions in order, against the targets in
+// order, with the last target getting anything left over.
+func (c *Combatant) DoAction(actionName, actionType string, force bool, targets []*Combatant) ([]ActionReport, error) {
+	var actions map[string]Action
 	switch actionType {
 	case "action":
-		action, ok = c.StatBlock.Actions[actionName]
+		actions = c.StatBlock.Actions
 	case "bonus action":
-		action, ok = c.StatBlock.BonusActions[actionName]
+		actions = c.StatBlock.BonusActions
 	case "reaction":
-		action, ok = c.StatBlock.Reactions[actionName]
+		actions = c.StatBlock.Reactions
+	case "legendary action":
+		actions = c.StatBlock.LegendaryActions
+	case "lair action":
+		actions = c.StatBlock.LairActions
 	}
 
+	action, ok := actions[actionName]
 	if !ok {
-		return fmt.Errorf("action not found: %s", actionName)
+		return nil, fmt.Errorf("action not found: %s", actionName)
 	}
 
-	sep := "-------------------------------------------------------"
-	fmt.Println(sep)
-
-	if action.AttackRoll.Present {
-		fmt.Printf(
-			"Attack roll:\n - %d to hit\n",
-			dice.D20.Roll(false, false)+action.AttackRoll.Modifier,
-		)
+	steps, err := c.multiattackSteps(actionName, action, force)
+	if err != nil {
+		return nil, err
+	}
+	if len(targets) > len(steps) {
+		return nil, fmt.Errorf("%s only has %d attack(s), so it can't take %d targets", actionName, len(steps), len(targets))
 	}
 
-	if action.SavingThrow.Present {
-		fmt.Printf(
-			"\nRequires DC %d %s saving throw!\n",
-			action.SavingThrow.DC,
-			strings.ToUpper(action.SavingThrow.Ability),
-		)
+	if action.Spent() && !force {
+		return nil, fmt.Errorf("%s can't use %s right now%s - use the force flag to take it anyway", c.StatBlock.Name, actionName, action.usage())
+	}
+	if actionType == "legendary action" {
+		if action.legendaryCost() > c.LegendaryActionsLeft() && !force {
+			return nil, fmt.Errorf(
+				"%s costs %d legendary actions, but %s only has %d left this round - use the force flag to take it anyway",
+				actionName,
+				action.legendaryCost(),
+				c.StatBlock.Name,
+				c.LegendaryActionsLeft(),
+			)
+		}
+		c.StatBlock.LegendaryActionPool.Used += action.legendaryCost()
 	}
+	actions[actionName] = action.spend()
+	if len(action.Multiattack) != 0 {
+		for _, step := range steps {
+			c.StatBlock.Actions[step.name] = c.StatBlock.Actions[step.name].spend()
+		}
+	}
+
+	sep := "-------------------------------------------------------"
+	fmt.Println(sep)
+
+	var reports []ActionReport
+	for i, step := range steps {
+		if len(steps) > 1 {
+			fmt.Printf("%s (%d/%d)\n", capitalize(strings.ReplaceAll(step.name, "_", " ")), i+1, len(steps))
+		}
 
-	fmt.Println("\nEffects:")
-	for name, effect := range action.Effects {
-		d, err := dice.ReadDiceExpression(effect.Roll)
+		if len(targets) == 0 {
+			err = c.rollAction(step.action)
+		} else {
+			var report ActionReport
+			report, err = c.resolveAction(step.action, targets[min(i, len(targets)-1)])
+			reports = append(reports, report)
+		}
 		if err != nil {
-			return err
+			return reports, err
 		}
 
-		fmt.Printf(" - %s: %d %s\n", name, d.Roll(false, false), effect.Type)
+		if len(steps) > 1 {
+			fmt.Println(sep)
+		}
 	}
 
-	fmt.Println(sep)
+	if len(steps) > 1 && len(targets) != 0 {
+		printActionSummary(reports)
+		fmt.Println(sep)
+	} else if len(steps) == 1 {
+		fmt.Println(sep)
+	}
 	fmt.Println(action.Description)
 	fmt.Println(sep)
 
-	return nil
+	return reports, nil
 }
 
-func (c Combatant) Save(dc int, ability string, advantage, disadvantage bool) (bool, error) {
-	mod, ok := c.StatBlock.Saves[ability]
-	if !ok {
-		switch ability {
-		case "str":
-			mod = c.StatBlock.Abilities.STR
-		case "dex":
-			mod = c.StatBlock.Abilities.DEX
-		case "con":
-			mod = c.StatBlock.Abilities.CON
-		case "int":
-			mod = c.StatBlock.Abilities.INT
-		case "wis":
-			mod = c.StatBlock.Abilities.WIS
-		case "cha":
-			mod = c.StatBlock.Abilities.CHA
-		default:
-			return false, fmt.Errorf("invalid ability: %s", ability)
-		}
+// Save makes a saving throw against the provided DC, with any advantage,
+// disadvantage, or automatic failure from the combatant's conditions. A
+// failure can be turned into a success with a legendary resistance.
+func (c *Combatant) Save(dc int, ability string, advantage, disadvantage bool) (SaveReport, error) {
+	mod, err := c.SaveModifier(ability)
+	if err != nil {
+		return SaveReport{}, err
+	}
+
+	report := SaveReport{Modifiers: c.SaveModifiers(ability)}
+	if !report.Modifiers.AutoFail {
+		report.Roll = dice.D20.Roll(
+			advantage || report.Modifiers.Advantage,
+			disadvantage || report.Modifiers.Disadvantage,
+		).Add(mod)
+		report.Success = report.Roll.Total >= dc
 	}
 
-	total := dice.D20.Roll(advantage, disadvantage) + mod
+	c.useLegendaryResistance(&report)
 
-	result := total >= dc
-	return result, nil
+	return report, nil
 }
 
 func (c Combatant) Display() {
@@ -183,9 +354,26 @@ func (c Combatant) Display() {
 
 	fmt.Println(sep)
 
-	fmt.Printf(" - HP: %d/%d\n", c.StatBlock.HP["current"], c.StatBlock.HP["max"])
+	if c.StatBlock.HP["temp"] > 0 {
+		fmt.Printf(" - HP: %d/%d (+%d temp)\n", c.StatBlock.HP["current"], c.StatBlock.HP["max"], c.StatBlock.HP["temp"])
+	} else {
+		fmt.Printf(" - HP: %d/%d\n", c.StatBlock.HP["current"], c.StatBlock.HP["max"])
+	}
+	if left, total := c.HitDiceLeft(); total > 0 {
+		fmt.Printf(" - Hit dice: %s (%d/%d left)\n", c.StatBlock.HitDice, left, total)
+	}
 	fmt.Printf(" - AC: %d\n", c.StatBlock.AC)
 	fmt.Printf(" - Speed: %d\n", c.StatBlock.Speed)
+	fmt.Printf(" - Proficiency bonus: %+d\n", c.ProficiencyBonus())
+	if len(c.StatBlock.Tags) != 0 {
+		fmt.Printf(" - Tags: @%s\n", strings.Join(c.StatBlock.Tags, ", @"))
+	}
+	for _, condition := range c.Conditions {
+		fmt.Printf(" - Condition: %s\n", condition)
+	}
+	if c.DeathSaves != nil {
+		fmt.Printf(" - Death saves: %s\n", c.DeathSaves)
+	}
 
 	fmt.Println(sep)
 
@@ -199,24 +387,17 @@ func (c Combatant) Display() {
 		c.StatBlock.Abilities.CHA,
 	}
 	for _, score := range abilityScores {
-		var scoreStr string
-		if score >= 10 {
-			scoreStr = fmt.Sprintf("  %d +%d  ", score, (score-10)/2)
-		} else if score == 9 {
-			scoreStr = fmt.Sprintf("  %d  +0  ", score)
-		} else {
-			scoreStr = fmt.Sprintf("  %d  %d  ", score, (score-10)/2)
-		}
-		fmt.Print(scoreStr)
+		fmt.Printf("  %-2d %+d  ", score, AbilityModifier(score))
 	}
 	fmt.Printf("\n")
 
-	if len(c.StatBlock.Saves) != 0 {
+	if saves := c.proficientSaves(); len(saves) != 0 {
 		fmt.Println(sep)
 
 		i := 0
 		fmt.Print("Saving Throws ")
-		for ability, modifier := range c.StatBlock.Saves {
+		for _, ability := range saves {
+			modifier, _ := c.SaveModifier(ability)
 			if modifier >= 0 {
 				prettyPrintListItem(
 					fmt.Sprintf("%s +%d", strings.ToUpper(ability), modifier),
@@ -234,10 +415,14 @@ func (c Combatant) Display() {
 		fmt.Printf("\n")
 	}
 
-	if len(c.StatBlock.Skills) != 0 {
+	if skills := c.proficientSkills(); len(skills) != 0 {
 		i := 0
 		fmt.Print("Skills ")
-		for skill, modifier := range c.StatBlock.Skills {
+		for _, skill := range skills {
+			modifier, err := c.SkillModifier(skill)
+			if err != nil {
+				continue
+			}
 			if modifier >= 0 {
 				prettyPrintListItem(
 					fmt.Sprintf("%s +%d", capitalize(strings.Replace(skill, "_", " ", -1)), modifier),
@@ -270,13 +455,7 @@ func (c Combatant) Display() {
 			prettyPrintListItem(fmt.Sprintf("%s %dft", sense, distance), "", &i)
 		}
 	}
-	passivePerception := 10
-	mod, ok := c.StatBlock.Skills["perception"]
-	if ok {
-		passivePerception += mod
-	} else {
-		passivePerception += c.StatBlock.Abilities.WIS
-	}
+	passivePerception := c.PassivePerception()
 	switch i {
 	case 0:
 	case 1:
@@ -316,8 +495,9 @@ func (c Combatant) Display() {
 
 		for name, action := range c.StatBlock.Actions {
 			fmt.Printf(
-				"\n%s. %s\n",
+				"\n%s%s. %s\n",
 				capitalize(strings.Replace(name, "_", " ", -1)),
+				action.usage(),
 				action.Description,
 			)
 		}
@@ -331,8 +511,9 @@ func (c Combatant) Display() {
 
 		for name, action := range c.StatBlock.BonusActions {
 			fmt.Printf(
-				"\n%s. %s\n",
+				"\n%s%s. %s\n",
 				capitalize(strings.Replace(name, "_", " ", -1)),
+				action.usage(),
 				action.Description,
 			)
 		}
@@ -346,8 +527,57 @@ func (c Combatant) Display() {
 
 		for name, action := range c.StatBlock.Reactions {
 			fmt.Printf(
-				"\n%s. %s\n",
+				"\n%s%s. %s\n",
 				capitalize(strings.Replace(name, "_", " ", -1)),
+				action.usage(),
+				action.Description,
+			)
+		}
+		fmt.Printf("\n")
+	}
+
+	if len(c.StatBlock.LegendaryActions) != 0 {
+		fmt.Println(sep)
+
+		fmt.Printf("Legendary Actions (%d/%d left)\n", c.LegendaryActionsLeft(), c.StatBlock.LegendaryActionPool.Max)
+
+		for name, action := range c.StatBlock.LegendaryActions {
+			cost := ""
+			if action.legendaryCost() > 1 {
+				cost = fmt.Sprintf(" (Costs %d Actions)", action.legendaryCost())
+			}
+			fmt.Printf(
+				"\n%s%s%s. %s\n",
+				capitalize(strings.Replace(name, "_", " ", -1)),
+				cost,
+				action.usage(),
+				action.Description,
+			)
+		}
+		fmt.Printf("\n")
+	}
+
+	if c.StatBlock.LegendaryResistances.Max > 0 {
+		fmt.Println(sep)
+
+		fmt.Printf(
+			"Legendary Resistance (%d/%d per %s left)\n",
+			c.LegendaryResistancesLeft(),
+			c.StatBlock.LegendaryResistances.Max,
+			c.StatBlock.LegendaryResistances.Per,
+		)
+	}
+
+	if len(c.StatBlock.LairActions) != 0 {
+		fmt.Println(sep)
+
+		fmt.Println("Lair Actions")
+
+		for name, action := range c.StatBlock.LairActions {
+			fmt.Printf(
+				"\n%s%s. %s\n",
+				capitalize(strings.Replace(name, "_", " ", -1)),
+				action.usage(),
 				action.Description,
 			)
 		}
@@ -360,19 +590,49 @@ func (c Combatant) Display() {
 
 type Action struct {
 	AttackRoll struct {
-		Present  bool `json:"present"`
-		Modifier int  `json:"modifier"`
+		Present   bool `json:"present"`
+		Modifier  int  `json:"modifier"`
+		CritRange int  `json:"crit_range"`
 	} `json:"attack_roll"`
 	SavingThrow struct {
-		Present bool   `json:"present"`
-		Ability string `json:"ability"`
-		DC      int    `json:"dc"`
+		Present             bool   `json:"present"`
+		Ability             string `json:"ability"`
+		DC                  int    `json:"dc"`
+		HalfEffectOnSuccess bool   `json:"half_effect_on_success"`
+		// Effects and Conditions only happen to a target that fails the save,
+		// unless HalfEffectOnSuccess gives it half the effects on a success
+		Effects    map[string]Effect `json:"effects"`
+		Conditions []Condition       `json:"conditions"`
 	} `json:"saving_throw"`
-	Effects map[string]struct {
-		Roll string `json:"roll"`
-		Type string `json:"type"`
-	} `json:"effects"`
-	Description string `json:"description"`
+	Effects  map[string]Effect `json:"effects"`
+	Recharge Recharge          `json:"recharge"`
+	Uses     Uses              `json:"uses"`
+	// Cost is how many legendary actions a legendary action takes up
+	Cost int `json:"cost"`
+	// Multiattack lists the actions a multiattack is made of, in order, like
+	// ["claw", "claw", "bite"]
+	Multiattack []string `json:"multiattack"`
+	Description string   `json:"description"`
+}
+
+type Effect struct {
+	Roll string `json:"roll"`
+	Type string `json:"type"`
+}
+
+type AttackReport struct {
+	Hit      bool
+	Critical bool
+	Fumble   bool
+}
+
+type SaveReport struct {
+	Success   bool
+	Roll      dice.RollResult
+	Modifiers RollModifiers
+	// LegendaryResistance means the save failed, but a legendary resistance
+	// made it a success
+	LegendaryResistance bool
 }
 
 type EffectReport struct {
@@ -383,6 +643,69 @@ type EffectReport struct {
 	DroppedToZero bool
 	BackAboveZero bool
 	TrueEffect    int
+	// TempAbsorbed is how much of the damage came off temporary hit points
+	TempAbsorbed int
+	// KeptTemp means new temporary hit points were lower than the ones the
+	// combatant already had, so they were ignored
+	KeptTemp bool
+	// ConcentrationSave is only set if the damage called for one
+	ConcentrationSave *SaveReport
+	ConcentrationDC   int
+	// LostConcentration is the spell the combatant stopped concentrating on
+	LostConcentration string
+	// The rest only happen to player characters
+	StartedDying      bool
+	DeathSaveFailures int
+	MassiveDamage     bool
+	Died              bool
+	WasDead           bool
+}
+
+// Notes describes everything worth mentioning about how damage or healing
+// of the provided type went, like resistances or a broken concentration.
+func (r EffectReport) Notes(effectType string) []string {
+	var notes []string
+	if r.WasAtZero {
+		notes = append(notes, "Target was already at 0 hit points!")
+	}
+	if r.WasImmune {
+		notes = append(notes, fmt.Sprintf("Target is immune to %s damage!", effectType))
+	}
+	if r.WasResistant {
+		notes = append(notes, fmt.Sprintf("Target is resistant to %s damage!", effectType))
+	}
+	if r.WasVulnerable {
+		notes = append(notes, fmt.Sprintf("Target is vulnerable to %s damage!", effectType))
+	}
+	if r.WasDead {
+		notes = append(notes, "Target is dead, so healing won't do anything!")
+	}
+	if r.BackAboveZero {
+		notes = append(notes, "Target is back above 0 hit points!")
+	}
+	switch {
+	case r.MassiveDamage:
+		notes = append(notes, "Target took massive damage and died instantly!")
+	case r.StartedDying:
+		notes = append(notes, "Target is unconscious and dying!")
+	case r.DeathSaveFailures > 0:
+		notes = append(notes, fmt.Sprintf("Target failed %d death save(s) from taking damage!", r.DeathSaveFailures))
+		if r.Died {
+			notes = append(notes, "Target died!")
+		}
+	}
+	if r.TempAbsorbed > 0 {
+		notes = append(notes, fmt.Sprintf("Target's temporary hit points absorbed %d damage!", r.TempAbsorbed))
+	}
+	if r.ConcentrationSave != nil {
+		notes = append(notes, fmt.Sprintf("Target made a DC %d concentration save: %s", r.ConcentrationDC, r.ConcentrationSave.Roll))
+	}
+	if r.LostConcentration != "" {
+		notes = append(notes, fmt.Sprintf("Target lost concentration on %s!", r.LostConcentration))
+	} else if r.ConcentrationSave != nil {
+		notes = append(notes, "Target kept concentrating!")
+	}
+	return notes
 }
 
 func prettyPrintListItem(item, indent string, i *int) {
@@ -0,0 +1,50 @@
+package combatant
+
+import "slices"
+
+// Conditions that leave a combatant incapacitated, which also breaks their
+// concentration
+var incapacitatingConditions = []string{"incapacitated", "paralyzed", "petrified", "stunned", "unconscious"}
+
+// Concentrate starts concentrating on a spell, and returns whatever spell
+// the combatant was concentrating on before.
+func (c *Combatant) Concentrate(spell string) string {
+	previous := c.Concentration
+	c.Concentration = spell
+	return previous
+}
+
+// DropConcentration stops concentrating, and returns the spell the combatant
+// was concentrating on.
+func (c *Combatant) DropConcentration() string {
+	return c.Concentrate("")
+}
+
+// checkConcentration makes the concentration save for taking damage, with a
+// DC of 10 or half the damage, whichever is higher. Dropping to 0 hit points
+// loses concentration without a save.
+func (c *Combatant) checkConcentration(dmg int, report *EffectReport) {
+	if c.Concentration == "" || dmg <= 0 {
+		return
+	}
+
+	if report.DroppedToZero {
+		report.LostConcentration = c.DropConcentration()
+		return
+	}
+
+	report.ConcentrationDC = max(10, dmg/2)
+	save, err := c.Save(report.ConcentrationDC, "con", false, false)
+	if err != nil {
+		return
+	}
+	report.ConcentrationSave = &save
+
+	if !save.Success {
+		report.LostConcentration = c.DropConcentration()
+	}
+}
+
+func incapacitates(condition string) bool {
+	return slices.Contains(incapacitatingConditions, condition)
+}
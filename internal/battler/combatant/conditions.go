@@ -0,0 +1,219 @@
+package combatant
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Condition is a condition currently affecting a combatant, like prone or
+// frightened.
+type Condition struct {
+	Name string `json:"name"`
+	// Rounds counts down at the end of each of the combatant's turns, and the
+	// condition ends when it hits 0. A condition that starts at 0 lasts until
+	// it gets removed.
+	Rounds int `json:"rounds"`
+	// Source is whoever caused the condition
+	Source string `json:"source"`
+	// UntilSourceTurnEnds ends the condition at the end of the source's next
+	// turn instead
+	UntilSourceTurnEnds bool `json:"until_source_turn_ends"`
+	SourceTurnStarted   bool `json:"source_turn_started"`
+	// Spell links the condition to a spell the source is concentrating on,
+	// so it ends as soon as they stop
+	Spell string `json:"spell,omitempty"`
+}
+
+func (c Condition) String() string {
+	text := c.Name
+	switch {
+	case c.UntilSourceTurnEnds:
+		text += fmt.Sprintf(" (until the end of %s's next turn)", c.Source)
+	case c.Rounds == 1:
+		text += " (1 round left)"
+	case c.Rounds > 1:
+		text += fmt.Sprintf(" (%d rounds left)", c.Rounds)
+	}
+	if c.Spell != "" {
+		text += fmt.Sprintf(" (while %s concentrates on %s)", c.Source, c.Spell)
+	}
+	return text
+}
+
+type rollEffect int
+
+const (
+	noEffect rollEffect = iota
+	advantage
+	disadvantage
+)
+
+// conditionEffect is what a condition does to the rolls made by and against
+// whoever has it.
+type conditionEffect struct {
+	attacks        rollEffect
+	attacksAgainst rollEffect
+	checks         rollEffect
+	saves          map[string]rollEffect
+	autoFailSaves  []string
+}
+
+// conditionEffects covers the conditions from the Player's Handbook. Prone
+// is missing its attacks against, since those depend on how far away the
+// attacker is.
+var conditionEffects = map[string]conditionEffect{
+	"blinded":       {attacks: disadvantage, attacksAgainst: advantage},
+	"charmed":       {},
+	"deafened":      {},
+	"frightened":    {attacks: disadvantage, checks: disadvantage},
+	"grappled":      {},
+	"incapacitated": {},
+	"invisible":     {attacks: advantage, attacksAgainst: disadvantage},
+	"paralyzed":     {attacksAgainst: advantage, autoFailSaves: []string{"str", "dex"}},
+	"petrified":     {attacksAgainst: advantage, autoFailSaves: []string{"str", "dex"}},
+	"poisoned":      {attacks: disadvantage, checks: disadvantage},
+	"prone":         {attacks: disadvantage},
+	"restrained":    {attacks: disadvantage, attacksAgainst: advantage, saves: map[string]rollEffect{"dex": disadvantage}},
+	"stunned":       {attacksAgainst: advantage, autoFailSaves: []string{"str", "dex"}},
+	"unconscious":   {attacksAgainst: advantage, autoFailSaves: []string{"str", "dex"}},
+}
+
+// KnownCondition reports whether the battler knows what a condition does.
+// Anything else can still be applied, it just won't change any rolls.
+func KnownCondition(name string) bool {
+	_, ok := conditionEffects[name]
+	return ok
+}
+
+// RollModifiers is the advantage and disadvantage that conditions add to a
+// roll, and which conditions they came from.
+type RollModifiers struct {
+	Advantage    bool
+	Disadvantage bool
+	AutoFail     bool
+	Reasons      []string
+}
+
+func (m *RollModifiers) add(effect rollEffect, reason string) {
+	switch effect {
+	case advantage:
+		m.Advantage = true
+		m.Reasons = append(m.Reasons, fmt.Sprintf("advantage from %s", reason))
+	case disadvantage:
+		m.Disadvantage = true
+		m.Reasons = append(m.Reasons, fmt.Sprintf("disadvantage from %s", reason))
+	}
+}
+
+// combine puts two sets of modifiers together, like an attacker's and its
+// target's.
+func (m RollModifiers) combine(other RollModifiers) RollModifiers {
+	return RollModifiers{
+		Advantage:    m.Advantage || other.Advantage,
+		Disadvantage: m.Disadvantage || other.Disadvantage,
+		AutoFail:     m.AutoFail || other.AutoFail,
+		Reasons:      append(slices.Clone(m.Reasons), other.Reasons...),
+	}
+}
+
+// ApplyCondition gives the combatant a condition, replacing it if they
+// already have it. Incapacitating conditions also break the combatant's
+// concentration, and the spell it was on gets returned.
+func (c *Combatant) ApplyCondition(condition Condition) (string, error) {
+	if slices.Contains(c.StatBlock.ConditionImmunities, condition.Name) {
+		return "", fmt.Errorf("%s is immune to being %s", c.StatBlock.Name, condition.Name)
+	}
+
+	c.RemoveCondition(condition.Name)
+	c.Conditions = append(c.Conditions, condition)
+
+	if incapacitates(condition.Name) {
+		return c.DropConcentration(), nil
+	}
+	return "", nil
+}
+
+// RemoveCondition takes a condition away, and reports whether the combatant
+// actually had it.
+func (c *Combatant) RemoveCondition(name string) bool {
+	before := len(c.Conditions)
+	c.Conditions = slices.DeleteFunc(c.Conditions, func(condition Condition) bool {
+		return condition.Name == name
+	})
+	return len(c.Conditions) != before
+}
+
+func (c Combatant) HasCondition(name string) bool {
+	return slices.ContainsFunc(c.Conditions, func(condition Condition) bool {
+		return condition.Name == name
+	})
+}
+
+// StartTurn is called when anyone's turn starts, so conditions that last
+// until the end of that combatant's next turn know it has started.
+func (c *Combatant) StartTurn(name string) {
+	for i, condition := range c.Conditions {
+		if condition.UntilSourceTurnEnds && condition.Source == name {
+			c.Conditions[i].SourceTurnStarted = true
+		}
+	}
+}
+
+// EndTurn is called when anyone's turn ends, and returns every condition
+// that ended along with it.
+func (c *Combatant) EndTurn(name string) []Condition {
+	var ended []Condition
+	remaining := c.Conditions[:0]
+	for _, condition := range c.Conditions {
+		switch {
+		case condition.UntilSourceTurnEnds:
+			if condition.Source == name && condition.SourceTurnStarted {
+				ended = append(ended, condition)
+				continue
+			}
+		case condition.Rounds > 0 && c.StatBlock.Name == name:
+			condition.Rounds--
+			if condition.Rounds == 0 {
+				ended = append(ended, condition)
+				continue
+			}
+		}
+		remaining = append(remaining, condition)
+	}
+	c.Conditions = remaining
+	return ended
+}
+
+// AttackModifiers is what the combatant's conditions do to its own attacks.
+func (c Combatant) AttackModifiers() RollModifiers {
+	var modifiers RollModifiers
+	for _, condition := range c.Conditions {
+		modifiers.add(conditionEffects[condition.Name].attacks, condition.Name)
+	}
+	return modifiers
+}
+
+// DefenseModifiers is what the combatant's conditions do to attacks made
+// against it.
+func (c Combatant) DefenseModifiers() RollModifiers {
+	var modifiers RollModifiers
+	for _, condition := range c.Conditions {
+		modifiers.add(conditionEffects[condition.Name].attacksAgainst, condition.Name)
+	}
+	return modifiers
+}
+
+// SaveModifiers is what the combatant's conditions do to its saving throws
+// of the provided ability.
+func (c Combatant) SaveModifiers(ability string) RollModifiers {
+	var modifiers RollModifiers
+	for _, condition := range c.Conditions {
+		effect := conditionEffects[condition.Name]
+		if slices.Contains(effect.autoFailSaves, ability) {
+			modifiers.AutoFail = true
+			modifiers.Reasons = append(modifiers.Reasons, fmt.Sprintf("automatic failure from %s", condition.Name))
+		}
+		modifiers.add(effect.saves[ability], condition.Name)
+	}
+	return modifiers
+}
@@ -0,0 +1,140 @@
+package combatant
+
+import (
+	"fmt"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+)
+
+// DeathSaves tracks a player character who's at 0 hit points.
+type DeathSaves struct {
+	Successes int  `json:"successes"`
+	Failures  int  `json:"failures"`
+	Stable    bool `json:"stable"`
+	Dead      bool `json:"dead"`
+}
+
+func (d DeathSaves) String() string {
+	switch {
+	case d.Dead:
+		return "dead"
+	case d.Stable:
+		return "stable"
+	}
+	return fmt.Sprintf("dying (%d successes, %d failures)", d.Successes, d.Failures)
+}
+
+type DeathSaveReport struct {
+	Roll    dice.RollResult
+	Natural int
+	Revived bool
+	Stable  bool
+	Died    bool
+}
+
+func (c Combatant) IsDying() bool {
+	return c.DeathSaves != nil && !c.DeathSaves.Stable && !c.DeathSaves.Dead
+}
+
+func (c Combatant) IsDead() bool {
+	return c.DeathSaves != nil && c.DeathSaves.Dead
+}
+
+// RollDeathSave rolls a death saving throw for a dying combatant.
+func (c *Combatant) RollDeathSave() (DeathSaveReport, error) {
+	if !c.IsDying() {
+		return DeathSaveReport{}, fmt.Errorf("%s isn't dying", c.StatBlock.Name)
+	}
+
+	roll := dice.D20.Roll(false, false)
+	report := c.RecordDeathSave(roll.Natural)
+	report.Roll = roll
+	return report, nil
+}
+
+// RecordDeathSave records a death saving throw that's already been rolled,
+// like one a player rolled themselves. A 10 or higher is a success, a 1
+// counts as two failures, and a 20 brings the combatant back with 1 hit
+// point.
+func (c *Combatant) RecordDeathSave(natural int) DeathSaveReport {
+	report := DeathSaveReport{Natural: natural}
+	if !c.IsDying() {
+		return report
+	}
+
+	switch {
+	case natural >= 20:
+		c.HealHP(1)
+		report.Revived = true
+		return report
+	case natural == 1:
+		c.DeathSaves.Failures += 2
+	case natural >= 10:
+		c.DeathSaves.Successes++
+	default:
+		c.DeathSaves.Failures++
+	}
+
+	c.checkDeathSaves()
+	report.Stable = c.DeathSaves.Stable
+	report.Died = c.DeathSaves.Dead
+
+	return report
+}
+
+// Stabilize stabilizes a dying combatant, like Spare the Dying or a
+// successful Medicine check does.
+func (c *Combatant) Stabilize() error {
+	if !c.IsDying() {
+		return fmt.Errorf("%s isn't dying", c.StatBlock.Name)
+	}
+
+	c.DeathSaves.Successes = 0
+	c.DeathSaves.Failures = 0
+	c.DeathSaves.Stable = true
+	return nil
+}
+
+// fallUnconscious starts a player character dying at 0 hit points.
+func (c *Combatant) fallUnconscious() {
+	c.DeathSaves = &DeathSaves{}
+	c.RemoveCondition("unconscious")
+	c.Conditions = append(c.Conditions, Condition{Name: "unconscious"})
+}
+
+// damageAtZero handles a player character taking damage while already at 0
+// hit points, which is a failed death save (or two, from a critical hit).
+func (c *Combatant) damageAtZero(dmg int, critical bool, report *EffectReport) {
+	if c.DeathSaves == nil || c.DeathSaves.Dead {
+		return
+	}
+
+	if dmg >= c.StatBlock.HP["max"] {
+		c.DeathSaves.Dead = true
+		report.MassiveDamage = true
+		report.Died = true
+		return
+	}
+
+	report.DeathSaveFailures = 1
+	if critical {
+		report.DeathSaveFailures = 2
+	}
+	c.DeathSaves.Failures += report.DeathSaveFailures
+	c.DeathSaves.Stable = false
+
+	c.checkDeathSaves()
+	report.Died = c.DeathSaves.Dead
+}
+
+func (c *Combatant) checkDeathSaves() {
+	switch {
+	case c.DeathSaves.Failures >= 3:
+		c.DeathSaves.Failures = 3
+		c.DeathSaves.Dead = true
+	case c.DeathSaves.Successes >= 3:
+		c.DeathSaves.Successes = 0
+		c.DeathSaves.Failures = 0
+		c.DeathSaves.Stable = true
+	}
+}
@@ -0,0 +1,154 @@
+package combatant
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+)
+
+// hitDice returns the sets of dice in the combatant's hit dice, biggest dice
+// first, so '3d10+2d8+10' returns 3d10 and 2d8.
+func (c Combatant) hitDice() ([]dice.Dice, error) {
+	if c.StatBlock.HitDice == "" {
+		return nil, fmt.Errorf("%s doesn't have any hit dice", c.StatBlock.Name)
+	}
+
+	expression, err := dice.ReadDiceExpression(c.StatBlock.HitDice)
+	if err != nil {
+		return nil, fmt.Errorf("%s has bad hit dice: %s", c.StatBlock.Name, err)
+	}
+
+	hitDice := expression.Dice()
+	slices.SortStableFunc(hitDice, func(a, b dice.Dice) int {
+		return cmp.Compare(b.Denomination, a.Denomination)
+	})
+	return hitDice, nil
+}
+
+// HitDiceLeft is how many hit dice the combatant hasn't spent yet, out of
+// how many it has.
+func (c Combatant) HitDiceLeft() (left int, total int) {
+	hitDice, err := c.hitDice()
+	if err != nil {
+		return 0, 0
+	}
+
+	for _, d := range hitDice {
+		total += d.Amount
+		left += max(d.Amount-c.HitDiceSpent[d.Denomination], 0)
+	}
+	return left, total
+}
+
+// RollHP rolls the combatant's hit dice for its max hit points, and starts
+// it off at full health.
+func (c *Combatant) RollHP() (dice.RollResult, error) {
+	if c.StatBlock.HitDice == "" {
+		return dice.RollResult{}, fmt.Errorf("%s doesn't have any hit dice to roll", c.StatBlock.Name)
+	}
+
+	expression, err := dice.ReadDiceExpression(c.StatBlock.HitDice)
+	if err != nil {
+		return dice.RollResult{}, fmt.Errorf("%s has bad hit dice: %s", c.StatBlock.Name, err)
+	}
+
+	roll := expression.Roll(false, false)
+	c.setMaxHP(roll.Total)
+
+	return roll, nil
+}
+
+// AverageHP sets the combatant's max hit points to the average of its hit
+// dice, rounded down the way stat blocks do it, and starts it off at full
+// health.
+func (c *Combatant) AverageHP() (int, error) {
+	if c.StatBlock.HitDice == "" {
+		return 0, fmt.Errorf("%s doesn't have any hit dice to average", c.StatBlock.Name)
+	}
+
+	expression, err := dice.ReadDiceExpression(c.StatBlock.HitDice)
+	if err != nil {
+		return 0, fmt.Errorf("%s has bad hit dice: %s", c.StatBlock.Name, err)
+	}
+
+	distribution, err := expression.Distribution(false, false)
+	if err != nil {
+		return 0, fmt.Errorf("can't average %s's hit dice: %s", c.StatBlock.Name, err)
+	}
+
+	// The tiny nudge keeps something like 127.49999999 from rounding down to 127
+	c.setMaxHP(int(math.Floor(distribution.Mean() + 1e-9)))
+
+	return c.StatBlock.HP["max"], nil
+}
+
+func (c *Combatant) setMaxHP(hp int) {
+	if c.StatBlock.HP == nil {
+		c.StatBlock.HP = map[string]int{}
+	}
+	c.StatBlock.HP["max"] = max(hp, 1)
+	c.StatBlock.HP["current"] = c.StatBlock.HP["max"]
+}
+
+// HitDieReport is one hit die spent during a short rest.
+type HitDieReport struct {
+	Roll    dice.RollResult
+	Healing EffectReport
+}
+
+// SpendHitDie rolls one of the combatant's unspent hit dice (the biggest one
+// left) plus its CON modifier, and heals that much.
+func (c *Combatant) SpendHitDie() (HitDieReport, error) {
+	hitDice, err := c.hitDice()
+	if err != nil {
+		return HitDieReport{}, err
+	}
+	if c.IsDead() {
+		return HitDieReport{}, fmt.Errorf("%s is dead, so it can't spend hit dice", c.StatBlock.Name)
+	}
+
+	for _, d := range hitDice {
+		if c.HitDiceSpent[d.Denomination] >= d.Amount {
+			continue
+		}
+
+		if c.HitDiceSpent == nil {
+			c.HitDiceSpent = map[int]int{}
+		}
+		c.HitDiceSpent[d.Denomination]++
+
+		die := dice.Dice{Amount: 1, Denomination: d.Denomination, Faces: d.Faces}
+		roll := die.Roll(false, false).Add(AbilityModifier(c.StatBlock.Abilities.CON))
+		return HitDieReport{Roll: roll, Healing: c.HealHP(max(roll.Total, 0))}, nil
+	}
+
+	return HitDieReport{}, fmt.Errorf("%s doesn't have any hit dice left to spend", c.StatBlock.Name)
+}
+
+// regainHitDice gives back half of the combatant's hit dice (at least one),
+// biggest first, the way a long rest does. It returns how many came back.
+func (c *Combatant) regainHitDice() int {
+	hitDice, err := c.hitDice()
+	if err != nil {
+		return 0
+	}
+
+	_, total := c.HitDiceLeft()
+	regaining := max(total/2, 1)
+	regained := 0
+	for _, d := range hitDice {
+		back := min(c.HitDiceSpent[d.Denomination], regaining-regained)
+		if back <= 0 {
+			continue
+		}
+		c.HitDiceSpent[d.Denomination] -= back
+		if c.HitDiceSpent[d.Denomination] == 0 {
+			delete(c.HitDiceSpent, d.Denomination)
+		}
+		regained += back
+	}
+	return regained
+}
@@ -0,0 +1,63 @@
+package combatant
+
+import "fmt"
+
+// prompt asks the person running the battler a yes or no question. Without
+// one, the answer is always no.
+var prompt func(question string) bool
+
+// SetPrompter sets how the battler asks yes or no questions, like whether a
+// boss should spend a legendary resistance.
+func SetPrompter(p func(question string) bool) {
+	prompt = p
+}
+
+// legendaryCost is how many legendary actions an action costs, which is
+// 1 unless the action says otherwise.
+func (a Action) legendaryCost() int {
+	return max(a.Cost, 1)
+}
+
+// LegendaryActionsLeft is how many legendary actions the combatant has left
+// this round.
+func (c Combatant) LegendaryActionsLeft() int {
+	return max(c.StatBlock.LegendaryActionPool.Max-c.StatBlock.LegendaryActionPool.Used, 0)
+}
+
+// ResetLegendaryActions gives the combatant back its legendary actions at
+// the start of its turn, and returns how many it got back.
+func (c *Combatant) ResetLegendaryActions() int {
+	restored := c.StatBlock.LegendaryActionPool.Used
+	c.StatBlock.LegendaryActionPool.Used = 0
+	return restored
+}
+
+func (c Combatant) LegendaryResistancesLeft() int {
+	return max(c.StatBlock.LegendaryResistances.Max-c.StatBlock.LegendaryResistances.Used, 0)
+}
+
+// useLegendaryResistance offers to turn a failed save into a success, as
+// long as the combatant has a legendary resistance left and the prompt says
+// to spend it.
+func (c *Combatant) useLegendaryResistance(report *SaveReport) {
+	if report.Success || c.LegendaryResistancesLeft() == 0 || prompt == nil {
+		return
+	}
+
+	question := fmt.Sprintf(
+		"%s failed the save. Use a legendary resistance to succeed instead? (%d left)",
+		c.StatBlock.Name,
+		c.LegendaryResistancesLeft(),
+	)
+	if !prompt(question) {
+		return
+	}
+
+	c.StatBlock.LegendaryResistances.Used++
+	report.Success = true
+	report.LegendaryResistance = true
+}
+
+func (c Combatant) HasLair() bool {
+	return len(c.StatBlock.LairActions) != 0
+}
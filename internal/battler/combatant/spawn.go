@@ -0,0 +1,42 @@
+package combatant
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Spawn makes an independent copy of the combatant under a new name, fresh
+// and at full health with every use back, for running several of the same
+// monster at once. Spawned combatants don't have a file, so they never get saved.
+func (c Combatant) Spawn(name string) (Combatant, error) {
+	// A trip through JSON copies every map and slice, so nothing is shared
+	// with the template
+	data, err := json.Marshal(c)
+	if err != nil {
+		return Combatant{}, fmt.Errorf("error copying %s: %s", c.StatBlock.Name, err)
+	}
+
+	var spawned Combatant
+	err = json.Unmarshal(data, &spawned)
+	if err != nil {
+		return Combatant{}, fmt.Errorf("error copying %s: %s", c.StatBlock.Name, err)
+	}
+
+	spawned.StatBlock.Name = name
+	spawned.StatBlock.FileName = ""
+	spawned.Template = c.StatBlock.Name
+
+	if spawned.StatBlock.HP == nil {
+		spawned.StatBlock.HP = map[string]int{}
+	}
+	spawned.StatBlock.HP["current"] = spawned.StatBlock.HP["max"]
+	delete(spawned.StatBlock.HP, "temp")
+	spawned.Conditions = nil
+	spawned.Concentration = ""
+	spawned.DeathSaves = nil
+	spawned.HitDiceSpent = nil
+	spawned.restoreUses(true)
+	spawned.ResetLegendaryActions()
+
+	return spawned, nil
+}
@@ -0,0 +1,142 @@
+package combatant
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Abilities lists the six abilities in the order stat blocks show them.
+var Abilities = []string{"str", "dex", "con", "int", "wis", "cha"}
+
+// skillAbilities is which ability each skill falls back on.
+var skillAbilities = map[string]string{
+	"acrobatics":      "dex",
+	"animal_handling": "wis",
+	"arcana":          "int",
+	"athletics":       "str",
+	"deception":       "cha",
+	"history":         "int",
+	"insight":         "wis",
+	"intimidation":    "cha",
+	"investigation":   "int",
+	"medicine":        "wis",
+	"nature":          "int",
+	"perception":      "wis",
+	"performance":     "cha",
+	"persuasion":      "cha",
+	"religion":        "int",
+	"sleight_of_hand": "dex",
+	"stealth":         "dex",
+	"survival":        "wis",
+}
+
+// AbilityScore returns the combatant's score for an ability, like 14 for
+// 'dex'.
+func (c Combatant) AbilityScore(ability string) (int, error) {
+	switch ability {
+	case "str":
+		return c.StatBlock.Abilities.STR, nil
+	case "dex":
+		return c.StatBlock.Abilities.DEX, nil
+	case "con":
+		return c.StatBlock.Abilities.CON, nil
+	case "int":
+		return c.StatBlock.Abilities.INT, nil
+	case "wis":
+		return c.StatBlock.Abilities.WIS, nil
+	case "cha":
+		return c.StatBlock.Abilities.CHA, nil
+	}
+	return 0, fmt.Errorf("invalid ability: %s", ability)
+}
+
+// ProficiencyBonus comes from the combatant's level if it has one, and its
+// challenge rating otherwise. Anything with neither gets +2.
+func (c Combatant) ProficiencyBonus() int {
+	if c.StatBlock.Level > 0 {
+		return 2 + (c.StatBlock.Level-1)/4
+	}
+	cr := max(int(math.Ceil(c.StatBlock.ChallengeRating)), 1)
+	return 2 + (cr-1)/4
+}
+
+// SaveModifier is what gets added to the combatant's saving throws of an
+// ability. A modifier written into the stat block's saves always wins,
+// otherwise it's the ability modifier plus proficiency if the combatant is
+// proficient.
+func (c Combatant) SaveModifier(ability string) (int, error) {
+	score, err := c.AbilityScore(ability)
+	if err != nil {
+		return 0, err
+	}
+
+	if mod, ok := c.StatBlock.Saves[ability]; ok {
+		return mod, nil
+	}
+
+	mod := AbilityModifier(score)
+	if slices.Contains(c.StatBlock.SaveProficiencies, ability) {
+		mod += c.ProficiencyBonus()
+	}
+	return mod, nil
+}
+
+// SkillModifier is what gets added to the combatant's checks with a skill,
+// worked out the same way as SaveModifier, with expertise doubling the
+// proficiency bonus.
+func (c Combatant) SkillModifier(skill string) (int, error) {
+	if mod, ok := c.StatBlock.Skills[skill]; ok {
+		return mod, nil
+	}
+
+	ability, ok := skillAbilities[skill]
+	if !ok {
+		return 0, fmt.Errorf("invalid skill: %s", skill)
+	}
+
+	score, err := c.AbilityScore(ability)
+	if err != nil {
+		return 0, err
+	}
+
+	mod := AbilityModifier(score)
+	switch {
+	case slices.Contains(c.StatBlock.SkillExpertise, skill):
+		mod += 2 * c.ProficiencyBonus()
+	case slices.Contains(c.StatBlock.SkillProficiencies, skill):
+		mod += c.ProficiencyBonus()
+	}
+	return mod, nil
+}
+
+func (c Combatant) PassivePerception() int {
+	mod, _ := c.SkillModifier("perception")
+	return 10 + mod
+}
+
+// proficientSaves returns every ability the combatant has a save modifier
+// for, in stat block order.
+func (c Combatant) proficientSaves() []string {
+	var saves []string
+	for _, ability := range Abilities {
+		_, listed := c.StatBlock.Saves[ability]
+		if listed || slices.Contains(c.StatBlock.SaveProficiencies, ability) {
+			saves = append(saves, ability)
+		}
+	}
+	return saves
+}
+
+// proficientSkills returns every skill the combatant has a modifier,
+// proficiency, or expertise for, in alphabetical order.
+func (c Combatant) proficientSkills() []string {
+	var skills []string
+	for skill := range c.StatBlock.Skills {
+		skills = append(skills, skill)
+	}
+	skills = append(skills, c.StatBlock.SkillProficiencies...)
+	skills = append(skills, c.StatBlock.SkillExpertise...)
+	slices.Sort(skills)
+	return slices.Compact(skills)
+}
@@ -0,0 +1,37 @@
+package combatant
+
+import "slices"
+
+// HasTag reports whether the combatant is part of a group, either because
+// it's been tagged with it or because it was spawned from a combatant with
+// that name.
+func (c Combatant) HasTag(tag string) bool {
+	return slices.Contains(c.StatBlock.Tags, tag) || (c.Template != "" && c.Template == tag)
+}
+
+// AddTag tags the combatant, and reports whether it wasn't tagged already.
+func (c *Combatant) AddTag(tag string) bool {
+	if slices.Contains(c.StatBlock.Tags, tag) {
+		return false
+	}
+	c.StatBlock.Tags = append(c.StatBlock.Tags, tag)
+	return true
+}
+
+// RemoveTag takes a tag away, and reports whether the combatant had it.
+func (c *Combatant) RemoveTag(tag string) bool {
+	before := len(c.StatBlock.Tags)
+	c.StatBlock.Tags = slices.DeleteFunc(c.StatBlock.Tags, func(t string) bool {
+		return t == tag
+	})
+	return len(c.StatBlock.Tags) != before
+}
+
+// Down is for combatants that are out of the fight, which is dead player
+// characters and anything else at 0 hit points.
+func (c Combatant) Down() bool {
+	if c.StatBlock.PlayerCharacter {
+		return c.IsDead()
+	}
+	return c.StatBlock.HP["current"] <= 0
+}
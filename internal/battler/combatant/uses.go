@@ -0,0 +1,149 @@
+package combatant
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+)
+
+// Recharge is for abilities like "Fire Breath (Recharge 5-6)", which can be
+// used again once a d6 rolled at the start of the combatant's turn lands on
+// On or higher.
+type Recharge struct {
+	On    int  `json:"on"`
+	Spent bool `json:"spent"`
+}
+
+// Uses is for abilities that can only be used Max times per Per, which is
+// one of "day", "short rest" or "long rest".
+type Uses struct {
+	Max  int    `json:"max"`
+	Per  string `json:"per"`
+	Used int    `json:"used"`
+}
+
+type RechargeReport struct {
+	Action    string
+	Roll      dice.RollResult
+	Recharged bool
+}
+
+// Spent reports whether the action has no uses left until it recharges or
+// the combatant rests.
+func (a Action) Spent() bool {
+	return a.Recharge.Spent || (a.Uses.Max > 0 && a.Uses.Used >= a.Uses.Max)
+}
+
+func (a Action) spend() Action {
+	if a.Recharge.On > 0 {
+		a.Recharge.Spent = true
+	}
+	if a.Uses.Max > 0 {
+		a.Uses.Used++
+	}
+	return a
+}
+
+// usage describes how many uses the action has left, like '(2/3 per day
+// left)', or returns an empty string for actions that can be used at will.
+func (a Action) usage() string {
+	switch {
+	case a.Recharge.On > 0 && a.Recharge.Spent:
+		return fmt.Sprintf(" (Recharge %s, spent)", a.rechargeRange())
+	case a.Recharge.On > 0:
+		return fmt.Sprintf(" (Recharge %s)", a.rechargeRange())
+	case a.Uses.Max > 0:
+		return fmt.Sprintf(" (%d/%d per %s left)", max(a.Uses.Max-a.Uses.Used, 0), a.Uses.Max, a.Uses.Per)
+	}
+	return ""
+}
+
+func (a Action) rechargeRange() string {
+	if a.Recharge.On >= 6 {
+		return "6"
+	}
+	return fmt.Sprintf("%d-6", a.Recharge.On)
+}
+
+// actionLists returns every kind of action the combatant has, so they can
+// all be gone through at once.
+func (c Combatant) actionLists() []map[string]Action {
+	return []map[string]Action{
+		c.StatBlock.Actions,
+		c.StatBlock.BonusActions,
+		c.StatBlock.Reactions,
+		c.StatBlock.LegendaryActions,
+		c.StatBlock.LairActions,
+	}
+}
+
+// RollRecharges rolls a d6 for each of the combatant's spent recharge
+// abilities, which happens at the start of each of its turns.
+func (c Combatant) RollRecharges() []RechargeReport {
+	var reports []RechargeReport
+	for _, actions := range c.actionLists() {
+		for _, name := range rollOrder(actions) {
+			action := actions[name]
+			if !action.Recharge.Spent {
+				continue
+			}
+
+			roll := dice.D6.Roll(false, false)
+			report := RechargeReport{Action: name, Roll: roll, Recharged: roll.Total >= action.Recharge.On}
+			if report.Recharged {
+				action.Recharge.Spent = false
+				actions[name] = action
+			}
+			reports = append(reports, report)
+		}
+	}
+	return reports
+}
+
+// Rest gives back the uses of every action that comes back after the rest,
+// and returns their names. A long rest brings back everything.
+func (c *Combatant) Rest(long bool) []string {
+	restored := c.restoreUses(long)
+
+	if long {
+		if regained := c.regainHitDice(); regained != 0 {
+			restored = append(restored, fmt.Sprintf("%d of its hit dice", regained))
+		}
+	}
+
+	return restored
+}
+
+// restoreUses gets back the uses, recharges, and legendary resistances a
+// rest of the given length restores, and returns the names of what came back.
+func (c *Combatant) restoreUses(long bool) []string {
+	var restored []string
+	for _, actions := range c.actionLists() {
+		for _, name := range slices.Sorted(maps.Keys(actions)) {
+			action := actions[name]
+			if !action.Spent() && action.Uses.Used == 0 {
+				continue
+			}
+			if !long && action.Uses.Per != "short rest" {
+				continue
+			}
+
+			action.Uses.Used = 0
+			if long {
+				action.Recharge.Spent = false
+			}
+			actions[name] = action
+			restored = append(restored, name)
+		}
+	}
+
+	resistances := &c.StatBlock.LegendaryResistances
+	if resistances.Used > 0 && (long || resistances.Per == "short rest") {
+		resistances.Used = 0
+		restored = append(restored, "legendary resistance")
+	}
+
+	return restored
+}
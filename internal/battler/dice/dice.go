@@ -2,101 +2,294 @@ package dice
 
 import (
 	"fmt"
-	"math/rand"
+	"slices"
+	"strings"
 )
 
 type Dice struct {
 	Amount       int
 	Denomination int
-	Modifier     int
+	// Faces lists what's written on each side of a custom die, like Fate
+	// dice's -1, 0 and +1. Empty means the usual 1 through Denomination.
+	Faces []int
+	// Selector is one of "kh", "kl", "dh" or "dl" (keep/drop highest/lowest),
+	// applied to SelectorCount of the rolled dice. Empty keeps every die.
+	Selector      string
+	SelectorCount int
+	// Reroll matches the faces that get rerolled, either once if RerollOnce
+	// is set or until the die lands on a face that doesn't match.
+	Reroll     Comparison
+	RerollOnce bool
+	// ExplodeOn matches the faces that get another die added on top of them.
+	ExplodeOn Comparison
+	// Minimum raises any face below it up to it, like Reliable Talent does.
+	// Zero means no minimum.
+	Minimum int
 }
 
-func (d Dice) Roll(adv, dis bool) int {
-	var total int
-	switch {
-	case adv && !dis:
-		// Advantage case, attack with advantage
-		total = d.adv()
-	case dis && !adv:
-		// Disadvantage case, attack with disadvantage
-		total = d.dis()
-	default:
-		// Advantage and disadvantage either cancel out or are not present,
-		// straight roll
-		total = d.straight()
-	}
-	return total
+// FudgeFaces are the faces of a Fate die, each one showing up twice.
+var FudgeFaces = []int{-1, -1, 0, 0, 1, 1}
+
+// Comparison matches die faces against a value, so '<3' matches 1s and 2s.
+// An empty Operator never matches anything.
+type Comparison struct {
+	Operator string
+	Value    int
 }
 
-func (d Dice) adv() (total int) {
-	for range d.Amount {
-		total += max(
-			(rand.Intn(d.Denomination)+1)+d.Modifier,
-			(rand.Intn(d.Denomination)+1)+d.Modifier,
-		)
+func (c Comparison) Matches(face int) bool {
+	switch c.Operator {
+	case "<":
+		return face < c.Value
+	case ">":
+		return face > c.Value
+	case "=":
+		return face == c.Value
 	}
-
-	return total
+	return false
 }
 
-func (d Dice) dis() (total int) {
-	for range d.Amount {
-		total += min(
-			(rand.Intn(d.Denomination)+1)+d.Modifier,
-			(rand.Intn(d.Denomination)+1)+d.Modifier,
-		)
+func (c Comparison) String() string {
+	if c.Operator == "=" {
+		return fmt.Sprint(c.Value)
 	}
+	return fmt.Sprintf("%s%d", c.Operator, c.Value)
+}
+
+// Upper limits on rerolls and explosions, just in case
+const (
+	maxRerolls    = 100
+	maxExplosions = 100
+)
 
-	return total
+// Roll rolls the dice, or with advantage or disadvantage, rolls them all
+// twice and keeps the higher or lower total.
+func (d Dice) Roll(adv, dis bool) RollResult {
+	return rollBestOfTwo(diceNode{dice: d}, adv, dis)
 }
 
-func (d Dice) straight() (total int) {
+// RollPerDie rolls the dice, but with advantage or disadvantage, rolls every
+// individual die twice and keeps the higher or lower face instead.
+func (d Dice) RollPerDie(adv, dis bool) RollResult {
+	return newRollResult(diceNode{dice: d}, adv, dis)
+}
+
+func (d Dice) roll(adv, dis bool) DiceResult {
+	result := DiceResult{Dice: d}
+
+	var active []int
 	for range d.Amount {
-		total += (rand.Intn(d.Denomination) + 1) + d.Modifier
-	}
-
-	return total
-}
-
-var D4 = Dice{Amount: 1, Denomination: 4, Modifier: 0}
-var D6 = Dice{Amount: 1, Denomination: 6, Modifier: 0}
-var D8 = Dice{Amount: 1, Denomination: 8, Modifier: 0}
-var D10 = Dice{Amount: 1, Denomination: 10, Modifier: 0}
-var D12 = Dice{Amount: 1, Denomination: 12, Modifier: 0}
-var D20 = Dice{Amount: 1, Denomination: 20, Modifier: 0}
-
-func ReadDiceExpression(expr string) (Dice, error) {
-	var amount int
-	var denomination int
-	var modifier int
-
-	_, err := fmt.Sscanf(expr, "%dd%d+%d", &amount, &denomination, &modifier)
-	if err != nil {
-		_, err := fmt.Sscanf(expr, "%dd%d-%d", &amount, &denomination, &modifier)
-		if err != nil {
-			_, err := fmt.Sscanf(expr, "%dd%d", &amount, &denomination)
-			if err != nil {
-				_, err = fmt.Sscanf(expr, "d%d+%d", &denomination, &modifier)
-				if err != nil {
-					_, err = fmt.Sscanf(expr, "d%d-%d", &denomination, &modifier)
-					if err != nil {
-						_, err = fmt.Sscanf(expr, "d%d", &denomination)
-						if err != nil {
-							return Dice{}, fmt.Errorf("invalid dice expression '%s'\ntry something like '2d4+2', '8d6', or 'd20'", expr)
-						}
-					}
-					modifier *= -1
-				}
-				amount = 1
+		switch {
+		case adv && !dis, dis && !adv:
+			// Advantage or disadvantage case, roll each die twice and keep
+			// the better or worse one
+			first, second := d.rollDie(), d.rollDie()
+			if (adv && second.Value > first.Value) || (dis && second.Value < first.Value) {
+				first.Dropped = true
+				active = append(active, len(result.Dies)+1)
+			} else {
+				second.Dropped = true
+				active = append(active, len(result.Dies))
 			}
+			result.Dies = append(result.Dies, first, second)
+		default:
+			// Advantage and disadvantage either cancel out or are not present,
+			// straight roll
+			active = append(active, len(result.Dies))
+			result.Dies = append(result.Dies, d.rollDie())
+		}
+	}
+
+	values := make([]int, len(active))
+	for i, die := range active {
+		values[i] = result.Dies[die].Value
+	}
+	for i, kept := range d.kept(values) {
+		if kept {
+			result.Total += values[i]
 		} else {
-			modifier *= -1
+			result.Dies[active[i]].Dropped = true
+		}
+	}
+
+	return result
+}
+
+// rollDie rolls a single die, applying any rerolls, explosions and minimums.
+func (d Dice) rollDie() DieResult {
+	var die DieResult
+
+	face := d.rollFace(&die)
+	for explosions := 0; d.ExplodeOn.Matches(d.raise(face)) && explosions < maxExplosions; explosions++ {
+		face = d.rollFace(&die)
+	}
+
+	return die
+}
+
+func (d Dice) rollFace(die *DieResult) int {
+	face := d.throw()
+
+	for rerolls := 0; d.Reroll.Matches(face) && rerolls < maxRerolls; rerolls++ {
+		die.Rerolled = append(die.Rerolled, face)
+		face = d.throw()
+		if d.RerollOnce {
+			break
+		}
+	}
+
+	die.Faces = append(die.Faces, face)
+	die.Value += d.raise(face)
+
+	return face
+}
+
+// throw picks a random face. A d100 is thrown the way it is at the table,
+// as a tens die and a units die, where 00 and 0 together make 100.
+func (d Dice) throw() int {
+	switch {
+	case len(d.Faces) != 0:
+		return d.Faces[roller.Intn(len(d.Faces))]
+	case d.Denomination == 100:
+		tens, units := roller.Intn(10)*10, roller.Intn(10)
+		if tens+units == 0 {
+			return 100
+		}
+		return tens + units
+	}
+	return roller.Intn(d.Denomination) + 1
+}
+
+// raise applies the dice's minimum to a face.
+func (d Dice) raise(face int) int {
+	if d.Minimum == 0 {
+		return face
+	}
+	return max(face, d.Minimum)
+}
+
+// faces returns every side of one die, so a d6 returns 1 through 6.
+func (d Dice) faces() []int {
+	if len(d.Faces) != 0 {
+		return d.Faces
+	}
+	faces := make([]int, d.Denomination)
+	for i := range faces {
+		faces[i] = i + 1
+	}
+	return faces
+}
+
+// highestFace is what '!' explodes on without a comparison.
+func (d Dice) highestFace() int {
+	return slices.Max(d.faces())
+}
+
+// kept reports which of the rolled faces survive the dice's selector.
+func (d Dice) kept(faces []int) []bool {
+	kept := make([]bool, len(faces))
+
+	order := make([]int, len(faces))
+	for i := range order {
+		order[i] = i
+	}
+	// Highest faces first, ties broken by position so the leftmost die wins
+	slices.SortStableFunc(order, func(a, b int) int {
+		return faces[b] - faces[a]
+	})
+
+	var from, to int
+	switch d.Selector {
+	case "kh":
+		from, to = 0, d.SelectorCount
+	case "kl":
+		from, to = len(faces)-d.SelectorCount, len(faces)
+	case "dh":
+		from, to = d.SelectorCount, len(faces)
+	case "dl":
+		from, to = 0, len(faces)-d.SelectorCount
+	default:
+		from, to = 0, len(faces)
+	}
+
+	for _, i := range order[max(from, 0):min(to, len(faces))] {
+		kept[i] = true
+	}
+
+	return kept
+}
+
+func (d Dice) validate() error {
+	switch d.Selector {
+	case "kh", "kl":
+		if d.SelectorCount < 1 || d.SelectorCount > d.Amount {
+			return fmt.Errorf("can't keep %d of %d dice", d.SelectorCount, d.Amount)
+		}
+	case "dh", "dl":
+		if d.SelectorCount < 1 || d.SelectorCount >= d.Amount {
+			return fmt.Errorf("can't drop %d of %d dice", d.SelectorCount, d.Amount)
 		}
 	}
 
-	if amount < 0 || denomination < 0 {
-		return Dice{}, fmt.Errorf("invalid dice expression '%s'\ntry something like '2d4+2', '8d6', or 'd20'", expr)
+	canStopRerolling := d.RerollOnce
+	canStopExploding := false
+	for _, face := range d.faces() {
+		if d.Reroll.Matches(face) && !d.RerollOnce {
+			continue
+		}
+		canStopRerolling = true
+		if !d.ExplodeOn.Matches(d.raise(face)) {
+			canStopExploding = true
+		}
+	}
+	if !canStopRerolling {
+		return fmt.Errorf("%s would reroll forever, since every face gets rerolled", d)
+	}
+	if !canStopExploding {
+		return fmt.Errorf("%s would explode forever, since every face explodes", d)
 	}
 
-	return Dice{Amount: amount, Denomination: denomination, Modifier: modifier}, nil
+	return nil
 }
+
+func (d Dice) String() string {
+	text := fmt.Sprintf("%dd%d", d.Amount, d.Denomination)
+	switch {
+	case slices.Equal(d.Faces, FudgeFaces):
+		text = fmt.Sprintf("%ddF", d.Amount)
+	case len(d.Faces) != 0:
+		faces := make([]string, len(d.Faces))
+		for i, face := range d.Faces {
+			faces[i] = fmt.Sprint(face)
+		}
+		text = fmt.Sprintf("%dd{%s}", d.Amount, strings.Join(faces, ","))
+	}
+	if d.Reroll.Operator != "" {
+		if d.RerollOnce {
+			text += "r" + d.Reroll.String()
+		} else {
+			text += "rr" + d.Reroll.String()
+		}
+	}
+	if d.ExplodeOn.Operator != "" {
+		text += "!"
+		if d.ExplodeOn != (Comparison{Operator: "=", Value: d.highestFace()}) {
+			text += d.ExplodeOn.String()
+		}
+	}
+	if d.Minimum != 0 {
+		text += fmt.Sprintf("min%d", d.Minimum)
+	}
+	if d.Selector != "" {
+		text += fmt.Sprintf("%s%d", d.Selector, d.SelectorCount)
+	}
+	return text
+}
+
+var D4 = Dice{Amount: 1, Denomination: 4}
+var D6 = Dice{Amount: 1, Denomination: 6}
+var D8 = Dice{Amount: 1, Denomination: 8}
+var D10 = Dice{Amount: 1, Denomination: 10}
+var D12 = Dice{Amount: 1, Denomination: 12}
+var D20 = Dice{Amount: 1, Denomination: 20}
+var D100 = Dice{Amount: 1, Denomination: 100}
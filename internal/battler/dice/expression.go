@@ -0,0 +1,249 @@
+package dice
+
+import "fmt"
+
+// Expression is a parsed dice expression, such as '2d6+1d4+3' or '(1d8+2)*2',
+// stored as a tree of dice terms, constants and arithmetic operations.
+type Expression struct {
+	text string
+	root node
+}
+
+// Roll rolls the expression, or with advantage or disadvantage, rolls the
+// whole expression twice and keeps the higher or lower total.
+func (e Expression) Roll(adv, dis bool) RollResult {
+	return rollBestOfTwo(e.root, adv, dis)
+}
+
+// RollPerDie rolls the expression, but with advantage or disadvantage, rolls
+// every individual die twice and keeps the higher or lower face instead.
+func (e Expression) RollPerDie(adv, dis bool) RollResult {
+	return newRollResult(e.root, adv, dis)
+}
+
+// Critical returns the expression with every set of dice in it rolled twice,
+// the way damage dice are on a critical hit.
+func (e Expression) Critical() Expression {
+	root := doubleDice(e.root)
+	return Expression{text: root.String(), root: root}
+}
+
+func doubleDice(n node) node {
+	switch n := n.(type) {
+	case diceNode:
+		return groupNode{inner: binaryNode{op: '+', left: n, right: n}}
+	case binaryNode:
+		return binaryNode{op: n.op, left: doubleDice(n.left), right: doubleDice(n.right)}
+	case negationNode:
+		return negationNode{operand: doubleDice(n.operand)}
+	case groupNode:
+		return groupNode{inner: doubleDice(n.inner)}
+	}
+	return n
+}
+
+// Dice returns every set of dice in the expression, in order, so
+// '3d10+2d8+5' returns 3d10 and 2d8.
+func (e Expression) Dice() []Dice {
+	return collectDice(e.root)
+}
+
+func collectDice(n node) []Dice {
+	switch n := n.(type) {
+	case diceNode:
+		return []Dice{n.dice}
+	case binaryNode:
+		return append(collectDice(n.left), collectDice(n.right)...)
+	case negationNode:
+		return collectDice(n.operand)
+	case groupNode:
+		return collectDice(n.inner)
+	}
+	return nil
+}
+
+func (e Expression) String() string {
+	return e.text
+}
+
+// node is one piece of an Expression. Rolling a node returns its total and a
+// breakdown of how it got there, and adds any dice it rolls to result. The
+// advantage and disadvantage passed to roll apply to each individual die.
+type node interface {
+	roll(adv, dis bool, result *RollResult) (int, string)
+	hasDice() bool
+	// modifier is the node's value with every die counted as a zero
+	modifier() int
+	distribution() (Distribution, error)
+	String() string
+}
+
+type constantNode struct {
+	value int
+}
+
+func (n constantNode) roll(adv, dis bool, result *RollResult) (int, string) {
+	return n.value, n.String()
+}
+
+func (n constantNode) hasDice() bool {
+	return false
+}
+
+func (n constantNode) modifier() int {
+	return n.value
+}
+
+func (n constantNode) distribution() (Distribution, error) {
+	return constantDistribution(n.value), nil
+}
+
+func (n constantNode) String() string {
+	return fmt.Sprint(n.value)
+}
+
+type diceNode struct {
+	dice Dice
+}
+
+func (n diceNode) roll(adv, dis bool, result *RollResult) (int, string) {
+	diceResult := n.dice.roll(adv, dis)
+	result.Dice = append(result.Dice, diceResult)
+	return diceResult.Total, diceResult.String()
+}
+
+func (n diceNode) hasDice() bool {
+	return true
+}
+
+func (n diceNode) modifier() int {
+	return 0
+}
+
+func (n diceNode) distribution() (Distribution, error) {
+	return n.dice.distribution()
+}
+
+func (n diceNode) String() string {
+	return n.dice.String()
+}
+
+type binaryNode struct {
+	op    byte
+	left  node
+	right node
+}
+
+func (n binaryNode) roll(adv, dis bool, result *RollResult) (int, string) {
+	left, leftText := n.left.roll(adv, dis, result)
+	right, rightText := n.right.roll(adv, dis, result)
+	text := fmt.Sprintf("%s%c%s", leftText, n.op, rightText)
+	switch n.op {
+	case '+':
+		return left + right, text
+	case '-':
+		return left - right, text
+	case '*':
+		return left * right, text
+	}
+	return 0, text
+}
+
+func (n binaryNode) hasDice() bool {
+	return n.left.hasDice() || n.right.hasDice()
+}
+
+func (n binaryNode) modifier() int {
+	left, right := n.left.modifier(), n.right.modifier()
+	switch n.op {
+	case '+':
+		return left + right
+	case '-':
+		return left - right
+	case '*':
+		return left * right
+	}
+	return 0
+}
+
+func (n binaryNode) distribution() (Distribution, error) {
+	left, err := n.left.distribution()
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.distribution()
+	if err != nil {
+		return nil, err
+	}
+
+	return combine(left, right, func(x, y int) int {
+		switch n.op {
+		case '+':
+			return x + y
+		case '-':
+			return x - y
+		case '*':
+			return x * y
+		}
+		return 0
+	})
+}
+
+func (n binaryNode) String() string {
+	return fmt.Sprintf("%s%c%s", n.left, n.op, n.right)
+}
+
+type negationNode struct {
+	operand node
+}
+
+func (n negationNode) roll(adv, dis bool, result *RollResult) (int, string) {
+	value, text := n.operand.roll(adv, dis, result)
+	return -value, "-" + text
+}
+
+func (n negationNode) hasDice() bool {
+	return n.operand.hasDice()
+}
+
+func (n negationNode) modifier() int {
+	return -n.operand.modifier()
+}
+
+func (n negationNode) distribution() (Distribution, error) {
+	operand, err := n.operand.distribution()
+	if err != nil {
+		return nil, err
+	}
+
+	return combine(constantDistribution(0), operand, func(x, y int) int { return x - y })
+}
+
+func (n negationNode) String() string {
+	return fmt.Sprintf("-%s", n.operand)
+}
+
+type groupNode struct {
+	inner node
+}
+
+func (n groupNode) roll(adv, dis bool, result *RollResult) (int, string) {
+	value, text := n.inner.roll(adv, dis, result)
+	return value, "(" + text + ")"
+}
+
+func (n groupNode) hasDice() bool {
+	return n.inner.hasDice()
+}
+
+func (n groupNode) modifier() int {
+	return n.inner.modifier()
+}
+
+func (n groupNode) distribution() (Distribution, error) {
+	return n.inner.distribution()
+}
+
+func (n groupNode) String() string {
+	return fmt.Sprintf("(%s)", n.inner)
+}
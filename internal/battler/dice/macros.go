@@ -0,0 +1,131 @@
+package dice
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// macros maps the name of every defined macro onto its dice expression, so
+// '$sneak' in an expression stands in for whatever 'sneak' was defined as.
+var macros = struct {
+	mu          sync.RWMutex
+	expressions map[string]string
+}{
+	expressions: map[string]string{},
+}
+
+// Macros returns a copy of every defined macro.
+func Macros() map[string]string {
+	macros.mu.RLock()
+	defer macros.mu.RUnlock()
+	return maps.Clone(macros.expressions)
+}
+
+func GetMacro(name string) (string, bool) {
+	macros.mu.RLock()
+	defer macros.mu.RUnlock()
+	expr, ok := macros.expressions[name]
+	return expr, ok
+}
+
+// SetMacro defines (or redefines) a macro, as long as its name is valid and
+// its expression parses.
+func SetMacro(name, expr string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	expr = strings.ToLower(strings.TrimSpace(expr))
+
+	err := validMacroName(name)
+	if err != nil {
+		return err
+	}
+
+	macros.mu.Lock()
+	defer macros.mu.Unlock()
+
+	defined := maps.Clone(macros.expressions)
+	defined[name] = expr
+	_, err = parse(expr, defined)
+	if err != nil {
+		return err
+	}
+
+	macros.expressions = defined
+	return nil
+}
+
+// SetMacros replaces every defined macro with the provided ones, as long as
+// they're all valid.
+func SetMacros(defined map[string]string) error {
+	lowered := make(map[string]string, len(defined))
+	for name, expr := range defined {
+		name = strings.ToLower(strings.TrimSpace(name))
+		err := validMacroName(name)
+		if err != nil {
+			return err
+		}
+		lowered[name] = strings.ToLower(strings.TrimSpace(expr))
+	}
+
+	for name, expr := range lowered {
+		_, err := parse(expr, lowered)
+		if err != nil {
+			return fmt.Errorf("macro '%s': %w", name, err)
+		}
+	}
+
+	macros.mu.Lock()
+	defer macros.mu.Unlock()
+	macros.expressions = lowered
+	return nil
+}
+
+// DeleteMacro removes a macro, as long as no other macro still uses it.
+func DeleteMacro(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	macros.mu.Lock()
+	defer macros.mu.Unlock()
+
+	if _, ok := macros.expressions[name]; !ok {
+		return fmt.Errorf("no macro named '%s'", name)
+	}
+
+	remaining := maps.Clone(macros.expressions)
+	delete(remaining, name)
+	for other, expr := range remaining {
+		_, err := parse(expr, remaining)
+		if err != nil {
+			return fmt.Errorf("can't delete macro '%s' while macro '%s' still uses it", name, other)
+		}
+	}
+
+	macros.expressions = remaining
+	return nil
+}
+
+func validMacroName(name string) error {
+	if name == "" {
+		return fmt.Errorf("macros need a name")
+	}
+
+	for i, r := range name {
+		if !isMacroRune(r) || (i == 0 && !unicode.IsLetter(r)) {
+			return fmt.Errorf("invalid macro name '%s': names start with a letter and only use letters, numbers, and underscores", name)
+		}
+	}
+
+	// A name like 'd20' would be impossible to tell apart from the dice
+	_, err := parse(name, nil)
+	if err == nil {
+		return fmt.Errorf("invalid macro name '%s': it's already a dice expression", name)
+	}
+
+	return nil
+}
+
+func isMacroRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
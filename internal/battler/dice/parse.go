@@ -0,0 +1,436 @@
+package dice
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	maxAmount       = 1000
+	maxDenomination = 1000
+)
+
+type tokenKind int
+
+const (
+	tokenEnd tokenKind = iota
+	tokenNumber
+	tokenWord
+	tokenSymbol
+	tokenMacro
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value int
+	pos   int
+}
+
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r):
+			start := i
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			value, err := strconv.Atoi(string(runes[start:i]))
+			if err != nil {
+				return nil, fmt.Errorf("number too large at position %d", start+1)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: string(runes[start:i]), value: value, pos: start})
+		case unicode.IsLetter(r):
+			start := i
+			for i < len(runes) && unicode.IsLetter(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, text: string(runes[start:i]), pos: start})
+		case r == '$':
+			start := i
+			i++
+			for i < len(runes) && isMacroRune(runes[i]) {
+				i++
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("expected a macro name after '$' at position %d", start+1)
+			}
+			tokens = append(tokens, token{kind: tokenMacro, text: string(runes[start+1 : i]), pos: start})
+		case strings.ContainsRune("+-*()!<>=%{},", r):
+			tokens = append(tokens, token{kind: tokenSymbol, text: string(r), pos: i})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character '%c' at position %d", r, i+1)
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEnd, pos: len(runes)})
+	return tokens, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+	macros map[string]string
+	// expanding holds the macros currently being expanded, to catch any
+	// macro that ends up referring back to itself
+	expanding map[string]bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEnd {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) acceptSymbol(symbol string) bool {
+	t := p.peek()
+	if t.kind == tokenSymbol && t.text == symbol {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expression := term (('+' | '-') term)*
+func (p *parser) parseExpression() (node, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if t.kind != tokenSymbol || (t.text != "+" && t.text != "-") {
+			return left, nil
+		}
+		p.next()
+
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = binaryNode{op: t.text[0], left: left, right: right}
+	}
+}
+
+// term := unary ('*' unary)*
+func (p *parser) parseTerm() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		t := p.peek()
+		if !p.acceptSymbol("*") {
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if left.hasDice() && right.hasDice() {
+			return nil, fmt.Errorf("dice can only be multiplied by constants, not other dice (position %d)", t.pos+1)
+		}
+		left = binaryNode{op: '*', left: left, right: right}
+	}
+}
+
+// unary := '-' unary | primary
+func (p *parser) parseUnary() (node, error) {
+	if p.acceptSymbol("-") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return negationNode{operand: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+// primary := number | dice | macro | '(' expression ')'
+func (p *parser) parsePrimary() (node, error) {
+	t := p.peek()
+	switch {
+	case t.kind == tokenMacro:
+		p.next()
+		return p.expandMacro(t)
+	case t.kind == tokenSymbol && t.text == "(":
+		p.next()
+		inner, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		if !p.acceptSymbol(")") {
+			return nil, fmt.Errorf("missing ')' at position %d", p.peek().pos+1)
+		}
+		return groupNode{inner: inner}, nil
+	case t.kind == tokenNumber:
+		p.next()
+		if p.peek().kind == tokenWord {
+			return p.parseDice(t.value)
+		}
+		return constantNode{value: t.value}, nil
+	case t.kind == tokenWord:
+		return p.parseDice(1)
+	case t.kind == tokenEnd:
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	return nil, fmt.Errorf("unexpected '%s' at position %d", t.text, t.pos+1)
+}
+
+// dice  := [number] 'd' sides modifiers
+// sides := number | '%' | 'f' | '{' face (',' face)* '}'
+func (p *parser) parseDice(amount int) (node, error) {
+	t := p.next()
+	if t.text != "d" && !strings.HasPrefix(t.text, "df") {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", t.text, t.pos+1)
+	}
+
+	if amount < 1 || amount > maxAmount {
+		return nil, fmt.Errorf("the amount of dice must be between 1 and %d, not %d", maxAmount, amount)
+	}
+
+	d := Dice{Amount: amount}
+
+	switch sides := p.peek(); {
+	case t.text != "d":
+		// Fate dice, where any modifiers straight after the 'f' got read as
+		// part of the same word, like '4dfkh2'
+		d.Faces = FudgeFaces
+		d.Denomination = len(FudgeFaces)
+		if rest := t.text[len("df"):]; rest != "" {
+			p.pos--
+			p.tokens[p.pos] = token{kind: tokenWord, text: rest, pos: t.pos + len("df")}
+		}
+	case sides.kind == tokenSymbol && sides.text == "%":
+		p.next()
+		d.Denomination = 100
+	case sides.kind == tokenSymbol && sides.text == "{":
+		p.next()
+		faces, err := p.parseFaces()
+		if err != nil {
+			return nil, err
+		}
+		d.Faces = faces
+		d.Denomination = len(faces)
+	case sides.kind == tokenNumber:
+		p.next()
+		if sides.value < 1 || sides.value > maxDenomination {
+			return nil, fmt.Errorf("dice must have between 1 and %d sides, not %d", maxDenomination, sides.value)
+		}
+		d.Denomination = sides.value
+	default:
+		return nil, fmt.Errorf("expected the number of sides after 'd' at position %d", t.pos+1)
+	}
+
+	err := p.parseModifiers(&d)
+	if err != nil {
+		return nil, err
+	}
+
+	return diceNode{dice: d}, nil
+}
+
+// face := ['-'] number
+func (p *parser) parseFaces() ([]int, error) {
+	var faces []int
+	for {
+		negative := p.acceptSymbol("-")
+		n := p.next()
+		if n.kind != tokenNumber {
+			return nil, fmt.Errorf("expected a face at position %d", n.pos+1)
+		}
+		if negative {
+			n.value = -n.value
+		}
+		faces = append(faces, n.value)
+		if len(faces) > maxDenomination {
+			return nil, fmt.Errorf("dice must have between 1 and %d sides", maxDenomination)
+		}
+
+		if p.acceptSymbol("}") {
+			return faces, nil
+		}
+		if !p.acceptSymbol(",") {
+			return nil, fmt.Errorf("missing '}' at position %d", p.peek().pos+1)
+		}
+	}
+}
+
+// macro := '$' name
+func (p *parser) expandMacro(t token) (node, error) {
+	expr, ok := p.macros[t.text]
+	if !ok {
+		return nil, fmt.Errorf("no macro named '%s' (position %d)", t.text, t.pos+1)
+	}
+	if p.expanding[t.text] {
+		return nil, fmt.Errorf("macro '%s' refers back to itself", t.text)
+	}
+
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return nil, fmt.Errorf("macro '%s': %w", t.text, err)
+	}
+
+	p.expanding[t.text] = true
+	defer delete(p.expanding, t.text)
+
+	inner := parser{tokens: tokens, macros: p.macros, expanding: p.expanding}
+	root, err := inner.parseAll()
+	if err != nil {
+		return nil, fmt.Errorf("macro '%s': %w", t.text, err)
+	}
+
+	switch root.(type) {
+	case diceNode, constantNode:
+		return root, nil
+	}
+	return groupNode{inner: root}, nil
+}
+
+// modifiers := (selector | reroll | explode | minimum)*
+// selector   := ('k' | 'kh' | 'kl' | 'dh' | 'dl') [number]
+// reroll     := ('r' | 'rr') comparison
+// explode    := '!' [comparison]
+// minimum    := 'min' number
+func (p *parser) parseModifiers(d *Dice) error {
+	seen := map[string]bool{}
+	for {
+		t := p.peek()
+
+		var modifier string
+		switch {
+		case t.kind == tokenSymbol && t.text == "!":
+			modifier = "explode"
+		case t.kind != tokenWord:
+			return d.validate()
+		case t.text == "k" || t.text == "kh" || t.text == "kl" || t.text == "dh" || t.text == "dl":
+			modifier = "keep/drop"
+		case t.text == "r" || t.text == "rr":
+			modifier = "reroll"
+		case t.text == "min":
+			modifier = "minimum"
+		default:
+			return fmt.Errorf("unknown dice modifier '%s' at position %d", t.text, t.pos+1)
+		}
+
+		if seen[modifier] {
+			return fmt.Errorf("only one %s modifier is allowed per set of dice (position %d)", modifier, t.pos+1)
+		}
+		seen[modifier] = true
+		p.next()
+
+		switch modifier {
+		case "keep/drop":
+			d.Selector = t.text
+			if t.text == "k" {
+				d.Selector = "kh"
+			}
+			d.SelectorCount = 1
+			if p.peek().kind == tokenNumber {
+				d.SelectorCount = p.next().value
+			}
+		case "reroll":
+			c, ok := p.parseComparison()
+			if !ok {
+				return fmt.Errorf("expected which faces to reroll after '%s' at position %d", t.text, t.pos+1)
+			}
+			d.Reroll = c
+			d.RerollOnce = t.text == "r"
+		case "explode":
+			c, ok := p.parseComparison()
+			if !ok {
+				c = Comparison{Operator: "=", Value: d.highestFace()}
+			}
+			d.ExplodeOn = c
+		case "minimum":
+			n := p.next()
+			if n.kind != tokenNumber {
+				return fmt.Errorf("expected a number after 'min' at position %d", t.pos+1)
+			}
+			d.Minimum = n.value
+		}
+	}
+}
+
+// comparison := ['<' | '>' | '='] number
+func (p *parser) parseComparison() (Comparison, bool) {
+	c := Comparison{Operator: "="}
+
+	start := p.pos
+	t := p.peek()
+	if t.kind == tokenSymbol && strings.Contains("<>=", t.text) {
+		c.Operator = t.text
+		p.next()
+	}
+
+	n := p.peek()
+	if n.kind != tokenNumber {
+		p.pos = start
+		return Comparison{}, false
+	}
+	p.next()
+	c.Value = n.value
+
+	return c, true
+}
+
+func (p *parser) parseAll() (node, error) {
+	root, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEnd {
+		return nil, fmt.Errorf("unexpected '%s' at position %d", t.text, t.pos+1)
+	}
+	return root, nil
+}
+
+// ReadDiceExpression parses a dice expression. A macro's name on its own is
+// read as that macro, so 'sneak' and '$sneak' mean the same thing.
+func ReadDiceExpression(expr string) (Expression, error) {
+	expr = strings.ToLower(strings.TrimSpace(expr))
+
+	macros.mu.RLock()
+	defer macros.mu.RUnlock()
+
+	if _, ok := macros.expressions[expr]; ok {
+		expr = "$" + expr
+	}
+
+	return parse(expr, macros.expressions)
+}
+
+func parse(expr string, defined map[string]string) (Expression, error) {
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return Expression{}, invalidExpression(expr, err)
+	}
+
+	p := parser{tokens: tokens, macros: defined, expanding: map[string]bool{}}
+	root, err := p.parseAll()
+	if err != nil {
+		return Expression{}, invalidExpression(expr, err)
+	}
+
+	return Expression{text: expr, root: root}, nil
+}
+
+func invalidExpression(expr string, err error) error {
+	return fmt.Errorf("invalid dice expression '%s': %s\ntry something like '2d4+2', '8d6', 'd20', '2d6+1d4+3', 'd%%', '4df', or '1d{1,1,2,2,3,4}'", expr, err)
+}
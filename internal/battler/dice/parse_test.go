@@ -0,0 +1,127 @@
+package dice
+
+import (
+	"strings"
+	"testing"
+)
+
+var testMacros = map[string]string{
+	"sneak": "3d6",
+	"smite": "2d8+$sneak",
+	"loop":  "$loop",
+}
+
+func TestParse(t *testing.T) {
+	previous := SetRoller(NewSeededRoller(1))
+	t.Cleanup(func() { SetRoller(previous) })
+
+	for _, test := range []struct {
+		expr     string
+		parsed   string
+		min, max int
+	}{
+		{expr: "1d20+5", parsed: "1d20+5", min: 6, max: 25},
+		{expr: "d20", parsed: "1d20", min: 1, max: 20},
+		{expr: "4d6kh3", parsed: "4d6kh3", min: 3, max: 18},
+		{expr: "4d6k", parsed: "4d6kh1", min: 1, max: 6},
+		{expr: "4d6dl1", parsed: "4d6dl1", min: 3, max: 18},
+		{expr: "2d20kl1-1", parsed: "2d20kl1-1", min: 0, max: 19},
+		{expr: "1d6!", parsed: "1d6!", min: 1},
+		{expr: "1d6!>5", parsed: "1d6!>5", min: 1},
+		{expr: "2d6r<3", parsed: "2d6r<3", min: 2, max: 12},
+		{expr: "2d6rr1", parsed: "2d6rr1", min: 4, max: 12},
+		{expr: "4d6min3", parsed: "4d6min3", min: 12, max: 24},
+		{expr: "4df", parsed: "4dF", min: -4, max: 4},
+		{expr: "d%", parsed: "1d100", min: 1, max: 100},
+		{expr: "1d{1,1,2,2,3,4}", parsed: "1d{1,1,2,2,3,4}", min: 1, max: 4},
+		{expr: "$sneak+2", parsed: "3d6+2", min: 5, max: 20},
+		{expr: "$smite", parsed: "(2d8+3d6)", min: 5, max: 34},
+		{expr: "2*(1d8+2)", parsed: "2*(1d8+2)", min: 6, max: 20},
+		{expr: "-1d4", parsed: "-1d4", min: -4, max: -1},
+	} {
+		e, err := parse(test.expr, testMacros)
+		if err != nil {
+			t.Errorf("%s: %s", test.expr, err)
+			continue
+		}
+		if got := e.root.String(); got != test.parsed {
+			t.Errorf("%s parsed as %s, want %s", test.expr, got, test.parsed)
+		}
+
+		d, err := e.Distribution(false, false)
+		if err != nil {
+			t.Errorf("%s: %s", test.expr, err)
+			continue
+		}
+		if d.Min() != test.min {
+			t.Errorf("%s: min %d, want %d", test.expr, d.Min(), test.min)
+		}
+		// Exploding dice have no real max
+		if test.max != 0 && d.Max() != test.max {
+			t.Errorf("%s: max %d, want %d", test.expr, d.Max(), test.max)
+		}
+
+		for range 200 {
+			total := e.Roll(false, false).Total
+			if total < d.Min() || (test.max != 0 && total > test.max) {
+				t.Errorf("%s rolled %d, outside of %d to %d", test.expr, total, d.Min(), d.Max())
+				break
+			}
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, test := range []struct {
+		expr, err string
+	}{
+		{expr: "1d1!", err: "1d1! would explode forever, since every face explodes"},
+		{expr: "2d6kh3", err: "can't keep 3 of 2 dice"},
+		{expr: "2d6dl3", err: "can't drop 3 of 2 dice"},
+		{expr: "1d2rr<3", err: "1d2rr<3 would reroll forever, since every face gets rerolled"},
+		{expr: "1d6*1d6", err: "dice can only be multiplied by constants, not other dice (position 4)"},
+		{expr: "$loop", err: "macro 'loop': macro 'loop' refers back to itself"},
+		{expr: "$nope", err: "no macro named 'nope' (position 1)"},
+		{expr: "1d6x", err: "unknown dice modifier 'x' at position 4"},
+		{expr: "1d6kh1kl1", err: "only one keep/drop modifier is allowed per set of dice (position 7)"},
+		{expr: "1d6r", err: "expected which faces to reroll after 'r' at position 4"},
+		{expr: "1d6min", err: "expected a number after 'min' at position 4"},
+		{expr: "(1d6", err: "missing ')' at position 5"},
+		{expr: "1d{1,2", err: "missing '}' at position 7"},
+		{expr: "1d0", err: "dice must have between 1 and 1000 sides, not 0"},
+		{expr: "0d6", err: "the amount of dice must be between 1 and 1000, not 0"},
+	} {
+		_, err := parse(test.expr, testMacros)
+		if err == nil {
+			t.Errorf("%s parsed, want error: %s", test.expr, test.err)
+			continue
+		}
+		want := "invalid dice expression '" + test.expr + "': " + test.err + "\n"
+		if !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("%s: got error %q, want %q", test.expr, err, want)
+		}
+	}
+}
+
+func TestReadDiceExpressionMacro(t *testing.T) {
+	previous := Macros()
+	t.Cleanup(func() { SetMacros(previous) })
+
+	if err := SetMacro("sneak", "3d6"); err != nil {
+		t.Fatal(err)
+	}
+	for _, expr := range []string{"sneak", "$sneak", " SNEAK "} {
+		e, err := ReadDiceExpression(expr)
+		if err != nil {
+			t.Errorf("%s: %s", expr, err)
+			continue
+		}
+		if got := e.root.String(); got != "3d6" {
+			t.Errorf("%s read as %s, want 3d6", expr, got)
+		}
+	}
+
+	if err := SetMacro("2d6", "1d4"); err == nil {
+		t.Errorf("a dice expression was accepted as a macro name")
+	}
+}
@@ -0,0 +1,138 @@
+package dice
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RollResult is the outcome of a roll, keeping every die that went into it
+// so the whole thing can be shown to the table.
+type RollResult struct {
+	Total int
+	// Dice holds one DiceResult for each set of dice in the expression, in order
+	Dice []DiceResult
+	// Modifier is what the expression adds up to without its dice, like the
+	// +5 in 1d20+5
+	Modifier int
+	// Natural is the face the first kept d20 landed on, or 0 without a d20
+	Natural int
+	// Dropped is the other roll when rolling with advantage or disadvantage
+	Dropped   *RollResult
+	breakdown string
+}
+
+func (r RollResult) Natural1() bool {
+	return r.Natural == 1
+}
+
+func (r RollResult) Natural20() bool {
+	return r.Natural == 20
+}
+
+// Breakdown shows how the total was reached, like '[6,1,4,4,2,6,3,5]+0'.
+func (r RollResult) Breakdown() string {
+	return r.breakdown
+}
+
+func (r RollResult) String() string {
+	return fmt.Sprintf("%s = %d", r.breakdown, r.Total)
+}
+
+// Add returns the result with a flat modifier added on, like an attack
+// bonus added to a d20.
+func (r RollResult) Add(modifier int) RollResult {
+	r.Total += modifier
+	r.Modifier += modifier
+	r.breakdown += fmt.Sprintf("%+d", modifier)
+	return r
+}
+
+func rollBestOfTwo(root node, adv, dis bool) RollResult {
+	if adv == dis {
+		// Advantage and disadvantage either cancel out or are not present,
+		// straight roll
+		return newRollResult(root, false, false)
+	}
+
+	first, second := newRollResult(root, false, false), newRollResult(root, false, false)
+
+	keep, drop := first, second
+	if (adv && second.Total > first.Total) || (dis && second.Total < first.Total) {
+		keep, drop = second, first
+	}
+
+	keep.Dropped = &drop
+	if adv {
+		keep.breakdown = fmt.Sprintf("max(%s, %s)", first.breakdown, second.breakdown)
+	} else {
+		keep.breakdown = fmt.Sprintf("min(%s, %s)", first.breakdown, second.breakdown)
+	}
+
+	return keep
+}
+
+func newRollResult(root node, adv, dis bool) RollResult {
+	result := RollResult{Modifier: root.modifier()}
+	result.Total, result.breakdown = root.roll(adv, dis, &result)
+
+	for _, d := range result.Dice {
+		if result.Natural == 0 && d.Dice.Denomination == 20 && len(d.Dice.Faces) == 0 {
+			for _, die := range d.Dies {
+				if !die.Dropped {
+					result.Natural = die.Faces[0]
+					break
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// DiceResult is the outcome of rolling one set of dice, such as the 8d6 in
+// 8d6+3.
+type DiceResult struct {
+	Dice  Dice
+	Dies  []DieResult
+	Total int
+}
+
+func (r DiceResult) String() string {
+	dies := make([]string, len(r.Dies))
+	for i, die := range r.Dies {
+		dies[i] = die.format(r.Dice.Minimum)
+	}
+	return fmt.Sprintf("[%s]", strings.Join(dies, ","))
+}
+
+// DieResult is a single die. Faces holds more than one face when the die
+// exploded, and Rerolled holds any faces that were thrown away by a reroll.
+type DieResult struct {
+	Faces    []int
+	Rerolled []int
+	Value    int
+	Dropped  bool
+}
+
+func (r DieResult) format(minimum int) string {
+	// Rerolled faces show up as '1r5', exploded ones as '6!+2', faces raised
+	// by a minimum as '3->10', and dropped dice as '~4~'
+	var b strings.Builder
+	for _, face := range r.Rerolled {
+		fmt.Fprintf(&b, "%dr", face)
+	}
+	for i, face := range r.Faces {
+		if i > 0 {
+			b.WriteString("!+")
+		}
+		if minimum != 0 && face < minimum {
+			fmt.Fprintf(&b, "%d->%d", face, minimum)
+		} else {
+			fmt.Fprint(&b, face)
+		}
+	}
+	if r.Dropped {
+		return fmt.Sprintf("~%s~", b.String())
+	}
+	return b.String()
+}
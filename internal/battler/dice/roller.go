@@ -0,0 +1,46 @@
+package dice
+
+import (
+	"math/rand"
+	"sync"
+)
+
+// Roller is the source of every die roll. A *rand.Rand satisfies it, so a
+// seeded one makes a whole session's rolls reproducible.
+type Roller interface {
+	Intn(n int) int
+}
+
+type globalRoller struct{}
+
+func (globalRoller) Intn(n int) int {
+	return rand.Intn(n)
+}
+
+var roller Roller = &lockedRoller{roller: globalRoller{}}
+
+// SetRoller replaces the source of every roll made by the dice package, and
+// returns the one it replaced so it can be put back.
+func SetRoller(r Roller) Roller {
+	previous := roller.(*lockedRoller).roller
+	roller = &lockedRoller{roller: r}
+	return previous
+}
+
+// NewSeededRoller returns a Roller that always produces the same rolls for
+// the same seed.
+func NewSeededRoller(seed int64) Roller {
+	return rand.New(rand.NewSource(seed))
+}
+
+// lockedRoller guards a Roller, since a *rand.Rand isn't safe to share.
+type lockedRoller struct {
+	mu     sync.Mutex
+	roller Roller
+}
+
+func (l *lockedRoller) Intn(n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.roller.Intn(n)
+}
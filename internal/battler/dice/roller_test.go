@@ -0,0 +1,46 @@
+package dice_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+	"github.com/45uperman/dndbattlercli/internal/golden"
+)
+
+func seededRolls(t *testing.T, seed int64) string {
+	t.Helper()
+	golden.Seed(t, seed)
+
+	var out strings.Builder
+	for _, roll := range []struct {
+		expr     string
+		adv, dis bool
+	}{
+		{expr: "1d20+5"},
+		{expr: "1d20+5", adv: true},
+		{expr: "2d6+3", dis: true},
+		{expr: "8d6"},
+		{expr: "4d6kh3"},
+		{expr: "2d20kl1-1"},
+		{expr: "1d6!+2"},
+		{expr: "2d10r1"},
+		{expr: "(1d8+2)*2"},
+	} {
+		e, err := dice.ReadDiceExpression(roll.expr)
+		if err != nil {
+			t.Fatalf("%s: %s", roll.expr, err)
+		}
+		fmt.Fprintf(&out, "%s (adv %t, dis %t): %s\n", roll.expr, roll.adv, roll.dis, e.Roll(roll.adv, roll.dis))
+	}
+	return out.String()
+}
+
+func TestSeededRolls(t *testing.T) {
+	got := seededRolls(t, 1)
+	if again := seededRolls(t, 1); again != got {
+		t.Errorf("the same seed rolled differently:\n%s\n%s", got, again)
+	}
+	golden.Check(t, "seeded_rolls", got)
+}
@@ -0,0 +1,251 @@
+package dice
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+)
+
+// Distribution maps every total a roll can come out to onto the probability
+// of it coming out to that total.
+type Distribution map[int]float64
+
+// Limits on how much work computing a distribution is allowed to do, so a
+// silly expression like 1000d1000kh500 fails instead of hanging the battler
+const (
+	maxOutcomes   = 200000
+	maxOperations = 50000000
+	// Explosions are followed until they become this unlikely
+	explosionCutoff = 1e-12
+)
+
+// Distribution works out the odds of every total the expression can roll,
+// where advantage and disadvantage work the same way they do for Roll.
+func (e Expression) Distribution(adv, dis bool) (Distribution, error) {
+	d, err := e.root.distribution()
+	if err != nil || adv == dis {
+		return d, err
+	}
+	return bestOfTwo(d, adv)
+}
+
+func (d Dice) Distribution(adv, dis bool) (Distribution, error) {
+	return Expression{root: diceNode{dice: d}}.Distribution(adv, dis)
+}
+
+// Outcomes returns every possible total, lowest first.
+func (d Distribution) Outcomes() []int {
+	return slices.Sorted(maps.Keys(d))
+}
+
+func (d Distribution) Min() int {
+	return slices.Min(d.Outcomes())
+}
+
+func (d Distribution) Max() int {
+	return slices.Max(d.Outcomes())
+}
+
+func (d Distribution) Mean() (mean float64) {
+	for outcome, p := range d {
+		mean += float64(outcome) * p
+	}
+	return mean
+}
+
+func (d Distribution) StdDev() float64 {
+	mean := d.Mean()
+	var variance float64
+	for outcome, p := range d {
+		variance += (float64(outcome) - mean) * (float64(outcome) - mean) * p
+	}
+	return math.Sqrt(variance)
+}
+
+// AtLeast returns the chance of rolling the provided total or higher.
+func (d Distribution) AtLeast(total int) (chance float64) {
+	for outcome, p := range d {
+		if outcome >= total {
+			chance += p
+		}
+	}
+	return min(chance, 1)
+}
+
+func constantDistribution(value int) Distribution {
+	return Distribution{value: 1}
+}
+
+// combine works out the distribution of applying op to one roll from each
+// of the provided distributions.
+func combine(a, b Distribution, op func(x, y int) int) (Distribution, error) {
+	if len(a)*len(b) > maxOperations {
+		return nil, fmt.Errorf("too many possible outcomes to work out exactly")
+	}
+
+	result := Distribution{}
+	for x, px := range a {
+		for y, py := range b {
+			result[op(x, y)] += px * py
+		}
+	}
+
+	if len(result) > maxOutcomes {
+		return nil, fmt.Errorf("too many possible outcomes to work out exactly")
+	}
+	return result, nil
+}
+
+func (d Dice) distribution() (Distribution, error) {
+	die := d.dieDistribution()
+
+	switch d.Selector {
+	case "kh":
+		return keepDistribution(die, d.Amount, d.SelectorCount, true)
+	case "kl":
+		return keepDistribution(die, d.Amount, d.SelectorCount, false)
+	case "dh":
+		return keepDistribution(die, d.Amount, d.Amount-d.SelectorCount, false)
+	case "dl":
+		return keepDistribution(die, d.Amount, d.Amount-d.SelectorCount, true)
+	}
+
+	outcomes := d.Amount*(die.Max()-die.Min()) + 1
+	if outcomes > maxOutcomes || outcomes*len(die)*d.Amount > maxOperations {
+		return nil, fmt.Errorf("too many possible outcomes to work out exactly")
+	}
+
+	total := constantDistribution(0)
+	for range d.Amount {
+		var err error
+		total, err = combine(total, die, func(x, y int) int { return x + y })
+		if err != nil {
+			return nil, err
+		}
+	}
+	return total, nil
+}
+
+// dieDistribution works out the distribution of a single die, with any
+// rerolls, minimums and explosions it has.
+func (d Dice) dieDistribution() Distribution {
+	sides := d.faces()
+	faceChance := 1 / float64(len(sides))
+
+	var rerolled int
+	for _, face := range sides {
+		if d.Reroll.Matches(face) {
+			rerolled++
+		}
+	}
+
+	faces := Distribution{}
+	for _, face := range sides {
+		var p float64
+		switch {
+		case d.RerollOnce:
+			// Either landed here straight away, or rerolled into it
+			p = float64(rerolled) * faceChance * faceChance
+			if !d.Reroll.Matches(face) {
+				p += faceChance
+			}
+		case d.Reroll.Matches(face):
+			p = 0
+		default:
+			p = 1 / float64(len(sides)-rerolled)
+		}
+		if p > 0 {
+			faces[d.raise(face)] += p
+		}
+	}
+
+	if d.ExplodeOn.Operator == "" {
+		return faces
+	}
+
+	die := Distribution{}
+	pending := Distribution{0: 1}
+	for explosions := 0; len(pending) != 0 && explosions <= maxExplosions; explosions++ {
+		exploding := Distribution{}
+		var remaining float64
+		for sofar, p := range pending {
+			for face, q := range faces {
+				if d.ExplodeOn.Matches(face) && explosions < maxExplosions {
+					exploding[sofar+face] += p * q
+					remaining += p * q
+				} else {
+					die[sofar+face] += p * q
+				}
+			}
+		}
+		if remaining < explosionCutoff {
+			break
+		}
+		pending = exploding
+	}
+	return die
+}
+
+// bestOfTwo works out the distribution of rolling twice and keeping the
+// higher roll, or the lower one without high.
+func bestOfTwo(d Distribution, high bool) (Distribution, error) {
+	return combine(d, d, func(x, y int) int {
+		if high {
+			return max(x, y)
+		}
+		return min(x, y)
+	})
+}
+
+// keepDistribution works out the distribution of rolling amount dice and
+// keeping the keep highest (or lowest) of them.
+//
+// Rather than going through every way the dice could land, it goes through
+// the faces from best to worst, deciding how many of the dice landed on each
+// one. The first keep dice handed out are the ones that get kept.
+func keepDistribution(die Distribution, amount, keep int, highest bool) (Distribution, error) {
+	if len(die)*amount*amount*keep*max(1, die.Max()-die.Min()) > maxOperations {
+		return nil, fmt.Errorf("too many possible outcomes to work out exactly")
+	}
+
+	faces := die.Outcomes()
+	if highest {
+		slices.Reverse(faces)
+	}
+
+	// states[used] maps the sum of the kept dice so far onto its probability,
+	// where used is how many dice have been handed out
+	states := make([]Distribution, amount+1)
+	states[0] = Distribution{0: 1}
+	for _, face := range faces {
+		p := die[face]
+		next := make([]Distribution, amount+1)
+		for used, sums := range states {
+			for sum, q := range sums {
+				for count := 0; used+count <= amount; count++ {
+					kept := min(count, max(0, keep-used))
+					weight := q * binomial(amount-used, count) * math.Pow(p, float64(count))
+					if weight == 0 {
+						continue
+					}
+					if next[used+count] == nil {
+						next[used+count] = Distribution{}
+					}
+					next[used+count][sum+kept*face] += weight
+				}
+			}
+		}
+		states = next
+	}
+
+	return states[amount], nil
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := range k {
+		result *= float64(n-i) / float64(i+1)
+	}
+	return result
+}
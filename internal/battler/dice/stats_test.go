@@ -0,0 +1,86 @@
+package dice
+
+import (
+	"math"
+	"testing"
+)
+
+func distribution(t *testing.T, expr string, adv, dis bool) Distribution {
+	t.Helper()
+	e, err := parse(expr, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d, err := e.Distribution(adv, dis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDistributionMean(t *testing.T) {
+	for _, test := range []struct {
+		expr     string
+		adv, dis bool
+		mean     float64
+	}{
+		{expr: "1d20", mean: 10.5},
+		{expr: "1d20", adv: true, mean: 13.825},
+		{expr: "1d20", dis: true, mean: 7.175},
+		{expr: "1d20", adv: true, dis: true, mean: 10.5},
+		{expr: "8d6", mean: 28},
+		{expr: "4d6kh3", mean: 12.2446},
+		{expr: "4d6dl1", mean: 12.2446},
+		{expr: "2d6r<3", mean: 8.3333},
+		{expr: "2d6rr1", mean: 8},
+		{expr: "1d6!", mean: 4.2},
+		{expr: "4d6min3", mean: 16},
+		{expr: "4df", mean: 0},
+		{expr: "d%", mean: 50.5},
+		{expr: "1d{1,1,2,2,3,4}", mean: 13.0 / 6},
+		{expr: "2*(1d8+2)", mean: 13},
+	} {
+		d := distribution(t, test.expr, test.adv, test.dis)
+
+		var total float64
+		for _, p := range d {
+			total += p
+		}
+		if math.Abs(total-1) > 1e-9 {
+			t.Errorf("%s: probabilities add up to %f", test.expr, total)
+		}
+
+		if mean := d.Mean(); math.Abs(mean-test.mean) > 1e-4 {
+			t.Errorf("%s (adv %t, dis %t): mean %.4f, want %.4f", test.expr, test.adv, test.dis, mean, test.mean)
+		}
+	}
+}
+
+func TestDistributionAtLeast(t *testing.T) {
+	for _, test := range []struct {
+		expr   string
+		total  int
+		chance float64
+	}{
+		{expr: "8d6", total: 30, chance: 0.3802},
+		{expr: "1d20", total: 11, chance: 0.5},
+		{expr: "1d20", total: 1, chance: 1},
+		{expr: "1d20", total: 21, chance: 0},
+		{expr: "2d6", total: 12, chance: 1.0 / 36},
+	} {
+		d := distribution(t, test.expr, false, false)
+		if chance := d.AtLeast(test.total); math.Abs(chance-test.chance) > 1e-4 {
+			t.Errorf("%s >= %d: %.4f, want %.4f", test.expr, test.total, chance, test.chance)
+		}
+	}
+}
+
+func TestDistributionTooLarge(t *testing.T) {
+	e, err := parse("1000d1000kh500", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Distribution(false, false); err == nil {
+		t.Errorf("1000d1000kh500 worked out a distribution instead of giving up")
+	}
+}
@@ -0,0 +1,98 @@
+package battler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Groups is every group built into Targets, which can't be used as tags.
+var Groups = []string{"all", "party", "all-enemies"}
+
+// Targets turns a target into the names of the combatants it means. A name
+// is just that combatant, and anything starting with '@' is a group: '@all'
+// is every combatant, '@party' is every player character, '@all-enemies' is
+// everyone else, and '@goblins' is every combatant tagged 'goblins' (or
+// spawned from a combatant called 'goblins'). Groups leave out anyone who's
+// already down, and templates that copies have been spawned from.
+func (b Battler) Targets(target string) ([]string, error) {
+	b.MU.RLock()
+	defer b.MU.RUnlock()
+
+	tag, isGroup := strings.CutPrefix(target, "@")
+	if !isGroup {
+		if _, ok := b.Combatants[target]; !ok {
+			return nil, fmt.Errorf("could not find combatant: %s", target)
+		}
+		return []string{target}, nil
+	}
+
+	var names []string
+	for _, name := range b.inPlay() {
+		c := b.Combatants[name]
+		if c.Down() {
+			continue
+		}
+
+		var inGroup bool
+		switch tag {
+		case "all":
+			inGroup = true
+		case "party":
+			inGroup = c.StatBlock.PlayerCharacter
+		case "all-enemies":
+			inGroup = !c.StatBlock.PlayerCharacter
+		default:
+			inGroup = c.HasTag(tag)
+		}
+		if inGroup {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("nobody left standing is in the group: %s", target)
+	}
+
+	return names, nil
+}
+
+// InPlay returns the names of every combatant except templates that copies
+// have been spawned from, since those are never actually in the fight.
+func (b Battler) InPlay() []string {
+	b.MU.RLock()
+	defer b.MU.RUnlock()
+
+	return b.inPlay()
+}
+
+func (b Battler) inPlay() []string {
+	templates := map[string]bool{}
+	for _, c := range b.Combatants {
+		if c.Template != "" {
+			templates[c.Template] = true
+		}
+	}
+
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(b.Combatants)) {
+		if !templates[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Tags returns every tag in use, along with who has it.
+func (b Battler) Tags() map[string][]string {
+	b.MU.RLock()
+	defer b.MU.RUnlock()
+
+	tags := map[string][]string{}
+	for _, name := range slices.Sorted(maps.Keys(b.Combatants)) {
+		for _, tag := range b.Combatants[name].StatBlock.Tags {
+			tags[tag] = append(tags[tag], name)
+		}
+	}
+	return tags
+}
@@ -0,0 +1,266 @@
+package battler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/combatant"
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+)
+
+// Initiative is the turn order of the current fight. Round is 0 until
+// initiative has been rolled.
+type Initiative struct {
+	Entries []InitiativeEntry
+	Turn    int
+	Round   int
+}
+
+type InitiativeEntry struct {
+	Name     string
+	Total    int
+	Modifier int
+	// Manual entries were typed in instead of rolled, like player characters
+	// rolling their own dice
+	Manual bool
+	// InBattler is false for anyone who isn't one of the battler's
+	// combatants, which also tends to mean player characters
+	InBattler bool
+	// LairOf is set for lair entries, which go on initiative count 20 and
+	// belong to whichever combatant's lair it is
+	LairOf     string
+	tiebreaker int
+}
+
+// RollInitiative rolls initiative for one of the battler's combatants.
+func (b Battler) RollInitiative(name string, adv, dis bool) (InitiativeEntry, dice.RollResult, error) {
+	c, ok := b.GetCombatant(name)
+	if !ok {
+		return InitiativeEntry{}, dice.RollResult{}, fmt.Errorf("could not find combatant: %s", name)
+	}
+
+	roll := dice.D20.Roll(adv, dis).Add(c.InitiativeModifier())
+	entry := InitiativeEntry{
+		Name:      name,
+		Total:     roll.Total,
+		Modifier:  c.InitiativeModifier(),
+		InBattler: true,
+	}
+
+	return entry, roll, nil
+}
+
+// ManualInitiative makes an entry for an initiative someone rolled
+// themselves, which doesn't need to belong to one of the battler's combatants.
+func (b Battler) ManualInitiative(name string, total int) InitiativeEntry {
+	entry := InitiativeEntry{Name: name, Total: total, Manual: true}
+
+	c, ok := b.GetCombatant(name)
+	if ok {
+		entry.Modifier = c.InitiativeModifier()
+		entry.InBattler = true
+	}
+
+	return entry
+}
+
+// StartInitiative replaces the turn order with the provided entries and
+// starts the fight at the top of round 1.
+func (b Battler) StartInitiative(entries []InitiativeEntry) TurnReport {
+	b.MU.Lock()
+	defer b.MU.Unlock()
+
+	b.Initiative.Entries = nil
+	for _, entry := range entries {
+		b.Initiative.insert(entry)
+		b.addLair(entry)
+	}
+	b.Initiative.Turn = 0
+	b.Initiative.Round = 1
+
+	if len(b.Initiative.Entries) == 0 {
+		return TurnReport{}
+	}
+	return b.startTurn()
+}
+
+// JoinInitiative adds an entry to a fight that's already going, without
+// changing whose turn it is.
+func (b Battler) JoinInitiative(entry InitiativeEntry) error {
+	b.MU.Lock()
+	defer b.MU.Unlock()
+
+	if b.Initiative.Round == 0 {
+		return fmt.Errorf("initiative hasn't been rolled yet")
+	}
+
+	if b.Initiative.insert(entry) <= b.Initiative.Turn {
+		b.Initiative.Turn++
+	}
+	b.addLair(entry)
+
+	return nil
+}
+
+// addLair gives the entry's combatant a lair entry on initiative count 20,
+// if it has lair actions and doesn't have one already. Must be called with
+// the lock held.
+func (b Battler) addLair(entry InitiativeEntry) {
+	c, ok := b.Combatants[entry.Name]
+	if !entry.InBattler || !ok || !c.HasLair() {
+		return
+	}
+
+	lair := InitiativeEntry{Name: entry.Name + "'s lair", Total: 20, LairOf: entry.Name}
+	if slices.ContainsFunc(b.Initiative.Entries, func(e InitiativeEntry) bool { return e.Name == lair.Name }) {
+		return
+	}
+
+	if b.Initiative.insert(lair) <= b.Initiative.Turn {
+		b.Initiative.Turn++
+	}
+}
+
+// insert adds an entry to the turn order (replacing any old entry with the
+// same name) and returns where it ended up.
+func (i *Initiative) insert(entry InitiativeEntry) int {
+	if old := slices.IndexFunc(i.Entries, func(e InitiativeEntry) bool { return e.Name == entry.Name }); old != -1 {
+		i.Entries = slices.Delete(i.Entries, old, old+1)
+		if old < i.Turn {
+			i.Turn--
+		}
+	}
+
+	// Rolled once for everyone, and only used if everything else is tied
+	entry.tiebreaker = dice.D20.Roll(false, false).Total
+
+	position, _ := slices.BinarySearchFunc(i.Entries, entry, compareInitiative)
+	i.Entries = slices.Insert(i.Entries, position, entry)
+
+	return position
+}
+
+// compareInitiative puts higher totals first. Lair entries lose every tie,
+// and other ties go to the higher modifier, then to entries typed in by hand
+// (the players), and then to whoever wins a roll-off.
+func compareInitiative(a, b InitiativeEntry) int {
+	switch {
+	case a.Total != b.Total:
+		return b.Total - a.Total
+	case (a.LairOf != "") != (b.LairOf != ""):
+		if a.LairOf != "" {
+			return 1
+		}
+		return -1
+	case a.Modifier != b.Modifier:
+		return b.Modifier - a.Modifier
+	case a.Manual != b.Manual:
+		if a.Manual {
+			return -1
+		}
+		return 1
+	}
+	return b.tiebreaker - a.tiebreaker
+}
+
+// TurnReport is everything that happened as one turn ended and the next
+// one started.
+type TurnReport struct {
+	Entry           InitiativeEntry
+	Round           int
+	EndedConditions []EndedCondition
+	// Recharges are the recharge rolls made at the start of the new turn
+	Recharges []combatant.RechargeReport
+	// LegendaryActions is how many legendary actions came back at the start
+	// of the new turn
+	LegendaryActions int
+}
+
+type EndedCondition struct {
+	Combatant string
+	Condition combatant.Condition
+}
+
+// NextTurn ends the current turn and moves on to the next entry in the turn
+// order, starting a new round after the last one.
+func (b Battler) NextTurn() (TurnReport, error) {
+	b.MU.Lock()
+	defer b.MU.Unlock()
+
+	if len(b.Initiative.Entries) == 0 {
+		return TurnReport{}, fmt.Errorf("initiative hasn't been rolled yet")
+	}
+
+	report := TurnReport{}
+	ending := b.Initiative.Entries[b.Initiative.Turn].Name
+	// Sorted so the ended conditions always come out in the same order
+	for _, name := range slices.Sorted(maps.Keys(b.Combatants)) {
+		for _, condition := range b.Combatants[name].EndTurn(ending) {
+			report.EndedConditions = append(report.EndedConditions, EndedCondition{Combatant: name, Condition: condition})
+		}
+	}
+
+	b.Initiative.Turn++
+	if b.Initiative.Turn == len(b.Initiative.Entries) {
+		b.Initiative.Turn = 0
+		b.Initiative.Round++
+	}
+
+	started := b.startTurn()
+	started.EndedConditions = report.EndedConditions
+
+	return started, nil
+}
+
+// startTurn starts the turn of whoever's up in the turn order, rolling their
+// recharges and refilling their legendary actions. The caller has to be
+// holding the lock.
+func (b Battler) startTurn() TurnReport {
+	report := TurnReport{
+		Entry: b.Initiative.Entries[b.Initiative.Turn],
+		Round: b.Initiative.Round,
+	}
+	for _, c := range b.Combatants {
+		c.StartTurn(report.Entry.Name)
+	}
+	if c, ok := b.Combatants[report.Entry.Name]; ok {
+		report.Recharges = c.RollRecharges()
+		report.LegendaryActions = c.ResetLegendaryActions()
+	}
+
+	return report
+}
+
+// PrevTurn goes back to the previous entry in the turn order, back into the
+// previous round if needed. Any conditions that ended along the way stay
+// ended.
+func (b Battler) PrevTurn() (InitiativeEntry, error) {
+	b.MU.Lock()
+	defer b.MU.Unlock()
+
+	if len(b.Initiative.Entries) == 0 {
+		return InitiativeEntry{}, fmt.Errorf("initiative hasn't been rolled yet")
+	}
+	if b.Initiative.Turn == 0 && b.Initiative.Round == 1 {
+		return InitiativeEntry{}, fmt.Errorf("already at the first turn of the fight")
+	}
+
+	b.Initiative.Turn--
+	if b.Initiative.Turn < 0 {
+		b.Initiative.Turn = len(b.Initiative.Entries) - 1
+		b.Initiative.Round--
+	}
+
+	return b.Initiative.Entries[b.Initiative.Turn], nil
+}
+
+// GetInitiative returns a copy of the current turn order.
+func (b Battler) GetInitiative() Initiative {
+	b.MU.RLock()
+	defer b.MU.RUnlock()
+
+	initiative := *b.Initiative
+	initiative.Entries = slices.Clone(b.Initiative.Entries)
+	return initiative
+}
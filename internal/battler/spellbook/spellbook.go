@@ -61,27 +61,19 @@ func (s Spell) Cast(targets []SpellTarget, spellFlags SpellFlags) {
 		// Do unavoidable effects
 		for _, unavoidable := range target.Flags.DoUnavoidables {
 			for range unavoidable.Repetitions {
-				result, err := s.UnavoidableEffects[unavoidable.EffectID-1].applyTo(target, levelsAboveBase, spellFlags, false)
+				result, breakdown, err := s.UnavoidableEffects[unavoidable.EffectID-1].applyTo(target, levelsAboveBase, spellFlags, false, false)
 				if err != nil {
 					fmt.Println(err)
 					continue
 				}
 
 				// Print
-				if s.UnavoidableEffects[unavoidable.EffectID-1].EffectType == "healing" {
-					fmt.Printf(
-						" - Target '%s' healed %d hit points!\n",
-						target.Target.StatBlock.Name,
-						result,
-					)
-				} else {
-					fmt.Printf(
-						" - Target '%s' took %d %s damage!",
-						target.Target.StatBlock.Name,
-						result,
-						s.UnavoidableEffects[unavoidable.EffectID-1].EffectType,
-					)
-				}
+				fmt.Printf(
+					" - Target '%s' %s! (%s)\n",
+					target.Target.StatBlock.Name,
+					s.UnavoidableEffects[unavoidable.EffectID-1].describe(result),
+					breakdown,
+				)
 			}
 		}
 	}
@@ -97,29 +89,34 @@ type SpellAttack struct {
 }
 
 func (sa SpellAttack) doTo(target SpellTarget, levelsAboveBase int, spellFlags SpellFlags, effectFlags EffectFlags, halfEffect bool) {
-	hit := target.Target.Hits(
-		dice.D20.Roll(
-			effectFlags.WithAdvantage,
-			effectFlags.WithAdvantage,
-		) + spellFlags.AttackModifiers[sa.ModifierKey],
-	)
+	modifiers := target.Target.DefenseModifiers()
+	attackRoll := dice.D20.Roll(
+		effectFlags.WithAdvantage || modifiers.Advantage,
+		effectFlags.WithDisadvantage || modifiers.Disadvantage,
+	).Add(spellFlags.AttackModifiers[sa.ModifierKey])
+	fmt.Printf("Attack roll: %s\n", attackRoll)
+	for _, reason := range modifiers.Reasons {
+		fmt.Printf(" - Rolled with %s\n", reason)
+	}
 
-	if hit {
-		fmt.Printf("Hit target '%s' with %s attack!    HIT!\n", target.Target.StatBlock.Name, sa.Name)
+	attack := target.Target.Attack(attackRoll, 0)
+
+	if attack.Hit {
+		if attack.Critical {
+			fmt.Printf("Critically hit target '%s' with %s attack!    CRIT!\n", target.Target.StatBlock.Name, sa.Name)
+		} else {
+			fmt.Printf("Hit target '%s' with %s attack!    HIT!\n", target.Target.StatBlock.Name, sa.Name)
+		}
 		// Do effects
 		for _, effect := range sa.Effects {
-			result, err := effect.applyTo(target, levelsAboveBase, spellFlags, halfEffect)
+			result, breakdown, err := effect.applyTo(target, levelsAboveBase, spellFlags, halfEffect, attack.Critical)
 			if err != nil {
 				fmt.Println(err)
 				continue
 			}
 
 			// Print
-			if effect.EffectType == "healing" {
-				fmt.Printf(" - Target healed %d hit points\n", result)
-			} else {
-				fmt.Printf(" - Target took %d %s damage\n", result, effect.EffectType)
-			}
+			fmt.Printf(" - Target %s (%s)\n", effect.describe(result), breakdown)
 		}
 		fmt.Printf("\n")
 
@@ -136,6 +133,8 @@ func (sa SpellAttack) doTo(target SpellTarget, levelsAboveBase int, spellFlags S
 				continue
 			}
 		}
+	} else if attack.Fumble {
+		fmt.Printf("Natural 1, missed target '%s' with %s attack!    FUMBLE!\n\n", target.Target.StatBlock.Name, sa.Name)
 	} else {
 		fmt.Printf("Missed target '%s' with %s attack!    MISS!\n\n", target.Target.StatBlock.Name, sa.Name)
 	}
@@ -151,33 +150,42 @@ type SpellSave struct {
 }
 
 func (ss SpellSave) forceOn(target SpellTarget, levelsAboveBase int, spellFlags SpellFlags, effectFlags EffectFlags) error {
-	saved, err := target.Target.Save(
+	save, err := target.Target.Save(
 		spellFlags.SaveDCs[ss.DCKey],
 		ss.Ability,
 		effectFlags.WithAdvantage,
-		effectFlags.WithAdvantage,
+		effectFlags.WithDisadvantage,
 	)
 	if err != nil {
 		return err
 	}
+	if !save.Modifiers.AutoFail {
+		fmt.Printf("Saving throw: %s\n", save.Roll)
+	}
+	for _, reason := range save.Modifiers.Reasons {
+		fmt.Printf(" - Rolled with %s\n", reason)
+	}
+	if save.LegendaryResistance {
+		fmt.Printf(
+			" - %s used a legendary resistance to succeed instead (%d left)\n",
+			target.Target.StatBlock.Name,
+			target.Target.LegendaryResistancesLeft(),
+		)
+	}
 
-	if saved {
+	if save.Success {
 		fmt.Printf("Target '%s' saved against %s!    SAVED!\n", target.Target.StatBlock.Name, ss.Name)
 		if ss.HalfEffectOnSuccess {
 			// Do effects
 			for _, effect := range ss.Effects {
-				result, err := effect.applyTo(target, levelsAboveBase, spellFlags, true)
+				result, breakdown, err := effect.applyTo(target, levelsAboveBase, spellFlags, true, false)
 				if err != nil {
 					fmt.Println(err)
 					continue
 				}
 
 				// Print
-				if effect.EffectType == "healing" {
-					fmt.Printf(" - Target still healed %d hit points\n", result)
-				} else {
-					fmt.Printf(" - Target still took %d %s damage\n", result, effect.EffectType)
-				}
+				fmt.Printf(" - Target still %s (%s)\n", effect.describe(result), breakdown)
 			}
 
 			// Do conditional attacks
@@ -191,18 +199,14 @@ func (ss SpellSave) forceOn(target SpellTarget, levelsAboveBase int, spellFlags
 
 		// Do effects
 		for _, effect := range ss.Effects {
-			result, err := effect.applyTo(target, levelsAboveBase, spellFlags, false)
+			result, breakdown, err := effect.applyTo(target, levelsAboveBase, spellFlags, false, false)
 			if err != nil {
 				fmt.Println(err)
 				continue
 			}
 
 			// Print
-			if effect.EffectType == "healing" {
-				fmt.Printf(" - Target healed %d hit points\n", result)
-			} else {
-				fmt.Printf(" - Target took %d %s damage\n", result, effect.EffectType)
-			}
+			fmt.Printf(" - Target %s (%s)\n", effect.describe(result), breakdown)
 		}
 		fmt.Printf("\n")
 
@@ -227,55 +231,74 @@ type SpellEffect struct {
 	Upcast         Upcast `json:"upcast"`
 }
 
-func (se SpellEffect) applyTo(target SpellTarget, levelsAboveBase int, spellFlags SpellFlags, halfEffect bool) (int, error) {
+func (se SpellEffect) applyTo(target SpellTarget, levelsAboveBase int, spellFlags SpellFlags, halfEffect, critical bool) (int, string, error) {
 	var result int
-
-	upcastBonus, err := se.Upcast.getUpcastBonus(levelsAboveBase)
-	if err != nil {
-		return 0, err
-	}
-
-	result += upcastBonus
-
-	result += spellFlags.EffectModifiers[se.ModifierKey]
+	var breakdown string
 
 	if se.DiceExpression != "" {
 		effectDice, err := dice.ReadDiceExpression(se.DiceExpression)
 		if err != nil {
-			return 0, err
+			return 0, "", err
+		}
+		if critical {
+			effectDice = effectDice.Critical()
 		}
 
-		result += effectDice.Roll(false, false)
+		roll := effectDice.Roll(false, false)
+		result += roll.Total
+		breakdown += roll.Breakdown()
+	}
+
+	upcastBonus, upcastBreakdown, err := se.Upcast.getUpcastBonus(levelsAboveBase, critical)
+	if err != nil {
+		return 0, "", err
 	}
 
+	result += upcastBonus
+	breakdown += upcastBreakdown
+
+	result += spellFlags.EffectModifiers[se.ModifierKey]
+	breakdown += fmt.Sprintf("%+d", spellFlags.EffectModifiers[se.ModifierKey])
+
 	if halfEffect {
 		result /= 2
+		breakdown = fmt.Sprintf("(%s)/2", breakdown)
 	}
 
 	var report combatant.EffectReport
-	if se.EffectType == "healing" {
+	switch se.EffectType {
+	case "healing":
 		report = target.Target.HealHP(result)
-	} else {
-		report = target.Target.TakeDMG(result, se.EffectType)
+	case "temp_hp":
+		report = target.Target.GainTempHP(result)
+		if report.KeptTemp {
+			fmt.Printf(" - Target kept the %d temporary hit points it already had!\n", report.TrueEffect)
+		}
+		return result, breakdown, nil
+	default:
+		if critical {
+			report = target.Target.TakeCritDMG(result, se.EffectType)
+		} else {
+			report = target.Target.TakeDMG(result, se.EffectType)
+		}
 	}
 
-	if report.WasAtZero {
-		fmt.Printf(" - Target was already at 0 hit points!\n")
-	}
-	if report.WasImmune {
-		fmt.Printf(" - Target is immune to %s damage!\n", se.EffectType)
-	}
-	if report.WasResistant {
-		fmt.Printf(" - Target is resistant to %s damage!\n", se.EffectType)
-	}
-	if report.WasVulnerable {
-		fmt.Printf(" - Target is vulnerable to %s damage!\n", se.EffectType)
-	}
-	if report.BackAboveZero {
-		fmt.Printf(" - Target is back above 0 hit points!\n")
+	for _, note := range report.Notes(se.EffectType) {
+		fmt.Printf(" - %s\n", note)
 	}
 
-	return report.TrueEffect, nil
+	return report.TrueEffect, breakdown, nil
+}
+
+// describe says what the effect did, like 'took 28 fire damage'.
+func (se SpellEffect) describe(result int) string {
+	switch se.EffectType {
+	case "healing":
+		return fmt.Sprintf("healed %d hit points", result)
+	case "temp_hp":
+		return fmt.Sprintf("gained %d temporary hit points", result)
+	}
+	return fmt.Sprintf("took %d %s damage", result, se.EffectType)
 }
 
 type Upcast struct {
@@ -284,22 +307,28 @@ type Upcast struct {
 	DiceExpression  string `json:"dice_expression"`
 }
 
-func (u Upcast) getUpcastBonus(levelsAboveBase int) (int, error) {
+func (u Upcast) getUpcastBonus(levelsAboveBase int, critical bool) (int, string, error) {
 	var upcastBonus int
+	var breakdown string
 
 	if u.DiceExpression != "" {
 		upcastDice, err := dice.ReadDiceExpression(u.DiceExpression)
 		if err != nil {
-			return 0, err
+			return 0, "", err
+		}
+		if critical {
+			upcastDice = upcastDice.Critical()
 		}
 
 		upcastLevel := min(levelsAboveBase/u.LevelsPerUpcast, u.MaxUpcast)
 		for range upcastLevel {
-			upcastBonus += upcastDice.Roll(false, false)
+			roll := upcastDice.Roll(false, false)
+			upcastBonus += roll.Total
+			breakdown += "+" + roll.Breakdown()
 		}
 	}
 
-	return upcastBonus, nil
+	return upcastBonus, breakdown, nil
 }
 
 type SpellFlags struct {
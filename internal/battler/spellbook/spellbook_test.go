@@ -0,0 +1,51 @@
+package spellbook
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/combatant"
+	"github.com/45uperman/dndbattlercli/internal/golden"
+)
+
+func load(t *testing.T, path string, v any) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCastSeeded(t *testing.T) {
+	golden.Seed(t, 3)
+
+	var fireball Spell
+	load(t, "testdata/fireball.json", &fireball)
+	var knight combatant.Combatant
+	// The knight is shared with the combatant tests
+	load(t, "../combatant/testdata/knight.json", &knight)
+
+	got := golden.Capture(t, func() {
+		fireball.Cast(
+			[]SpellTarget{{
+				Target: &knight,
+				Flags: TargetFlags{
+					DoAttacks:      []DoEffect{{EffectID: 1, Repetitions: 1}},
+					DoSaves:        []DoEffect{{EffectID: 1, Repetitions: 1}},
+					DoUnavoidables: []DoEffect{{EffectID: 1, Repetitions: 1}},
+				},
+			}},
+			SpellFlags{
+				CastingLevel:    3,
+				AttackModifiers: map[string]int{"am1": 7},
+				EffectModifiers: map[string]int{"em1": 0},
+				SaveDCs:         map[string]int{"dc1": 15},
+			},
+		)
+	})
+	golden.Check(t, "cast", got)
+}
@@ -0,0 +1,66 @@
+// Package golden holds the helpers shared by the golden file tests, which
+// check what the battler prints against testdata/*.golden.
+package golden
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Seed makes every roll for the rest of the test come from a roller seeded
+// with seed, and puts the old roller back afterwards.
+func Seed(t *testing.T, seed int64) {
+	t.Helper()
+	previous := dice.SetRoller(dice.NewSeededRoller(seed))
+	t.Cleanup(func() { dice.SetRoller(previous) })
+}
+
+// Capture returns everything f prints.
+func Capture(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	out := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		out <- string(data)
+	}()
+
+	f()
+	w.Close()
+	return <-out
+}
+
+// Check compares got against testdata/name.golden, or rewrites it with the
+// -update flag.
+func Check(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("output doesn't match %s\n got:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -2,12 +2,14 @@ package process
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 
 	"github.com/45uperman/dndbattlercli/internal/battler"
 	"github.com/45uperman/dndbattlercli/internal/battler/combatant"
+	"github.com/45uperman/dndbattlercli/internal/battler/dice"
 	"github.com/45uperman/dndbattlercli/internal/battler/spellbook"
 )
 
@@ -24,6 +26,11 @@ func SaveFiles(b battler.Battler) error {
 	defer b.MU.RUnlock()
 
 	for _, combatant := range b.Combatants {
+		if combatant.StatBlock.FileName == "" {
+			// Spawned combatants only last as long as the battler is running
+			continue
+		}
+
 		data, err := json.MarshalIndent(combatant, "", "  ")
 		if err != nil {
 			return fmt.Errorf("error marshaling %s: %s", combatant.StatBlock.Name, err)
@@ -35,6 +42,16 @@ func SaveFiles(b battler.Battler) error {
 		}
 	}
 
+	data, err := json.MarshalIndent(dice.Macros(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling macros: %s", err)
+	}
+
+	err = os.WriteFile(root+"/battle_files/macros.json", data, 0777)
+	if err != nil {
+		fmt.Printf("error writing file macros.json: %s\n", err)
+	}
+
 	return nil
 }
 
@@ -72,9 +89,33 @@ func LoadFiles() (battler.Battler, error) {
 		return battler.Battler{}, fmt.Errorf("could not load files because of error: %s", err)
 	}
 
+	err = loadMacros(root + "/battle_files/macros.json")
+	if err != nil {
+		return battler.Battler{}, fmt.Errorf("could not load macros because of error: %s", err)
+	}
+
 	return newBattler, nil
 }
 
+func loadMacros(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// No macros have been saved yet
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var macros map[string]string
+	err = json.Unmarshal(data, &macros)
+	if err != nil {
+		return err
+	}
+
+	return dice.SetMacros(macros)
+}
+
 func loadFile(path string, info os.FileInfo, b *battler.Battler, objectType string, err error) error {
 	if err != nil {
 		return err
@@ -94,6 +135,14 @@ func loadFile(path string, info os.FileInfo, b *battler.Battler, objectType stri
 				return err
 			}
 
+			// Hit dice stand in for max HP when there isn't one
+			if c.StatBlock.HitDice != "" && c.StatBlock.HP["max"] == 0 {
+				_, err = c.AverageHP()
+				if err != nil {
+					return err
+				}
+			}
+
 			b.AddCombatant(c)
 		case "spell":
 			var s spellbook.Spell
@@ -2,9 +2,14 @@ package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"maps"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/45uperman/dndbattlercli/internal/battler"
 	"github.com/45uperman/dndbattlercli/internal/battler/combatant"
@@ -32,6 +37,7 @@ type config struct {
 	helpPrintList     []string
 	isRunning         bool
 	selection         *combatant.Combatant
+	seed              int64
 }
 
 var cfg *config
@@ -65,9 +71,12 @@ func init() {
 			},
 			"dmg": {
 				name:        "dmg",
-				example:     "dmg 28, fire",
-				description: "Deals the provided amount of damage of the provided type to the selected combatant",
-				callback:    commandDmg,
+				example:     "dmg 28, fire, @goblins, blabby the blastoise --crit",
+				description: "Deals the provided amount of damage of the provided type to the selected combatant, or to the\n      provided targets, which can be groups like @goblins (see the tag command) or @all",
+				flags: map[string]string{
+					"--crit": "tells the battler the damage came from a critical hit, which counts as two failed death saves\n   against a player character at 0 hit points",
+				},
+				callback: commandDmg,
 			},
 			"heal": {
 				name:        "heal",
@@ -75,6 +84,22 @@ func init() {
 				description: "Heals the selected combatant by the provided amount of hp",
 				callback:    commandHeal,
 			},
+			"thp": {
+				name:        "thp",
+				example:     "thp 10",
+				description: "Gives the selected combatant the provided amount of temporary hit points, unless it already has more",
+				callback:    commandTHP,
+			},
+			"spawn": {
+				name:        "spawn",
+				example:     "spawn goblin 6 --roll",
+				description: "Adds the provided number of copies of a combatant (1 if there's no number), named 'goblin 1', 'goblin 2'\n      and so on, each with their own hit points and conditions - copies aren't saved when you exit",
+				flags: map[string]string{
+					"--roll": "tells the battler to roll each copy's hit points from the combatant's hit_dice",
+					"--avg":  "tells the battler to give each copy the average hit points of the combatant's hit_dice",
+				},
+				callback: commandSpawn,
+			},
 			"attack": {
 				name:        "attack",
 				example:     "attack 18",
@@ -87,16 +112,63 @@ func init() {
 				description: "Makes a saving throw using the selected comatant's saving throw modifier of the provided ability\n      against the provided DC and displays the result",
 				callback:    commandSave,
 			},
+			"check": {
+				name:        "check",
+				example:     "check athletics --adv --dc 15",
+				description: "Makes an ability check using the selected combatant's modifier for the provided skill or ability\n      (like 'stealth' or 'str') and displays the result",
+				flags: map[string]string{
+					"--adv": "tells the battler to make the check with advantage",
+					"--dis": "tells the battler to make the check with disadvantage",
+					"--dc":  "tells the battler the DC to compare the check to",
+				},
+				callback: commandCheck,
+			},
+			"contest": {
+				name:        "contest",
+				example:     "contest athletics, goblin 3 --skill acrobatics",
+				description: "Has the selected combatant make a check with the provided skill or ability against a check made\n      by the provided combatant, and displays who won. Without --skill, athletics gets resisted\n      with whichever of athletics or acrobatics the other combatant is better at (for grapples and\n      shoves), and anything else gets resisted with the same skill",
+				flags: map[string]string{
+					"--adv":   "tells the battler that combatant makes its check with advantage",
+					"--dis":   "tells the battler that combatant makes its check with disadvantage",
+					"--skill": "tells the battler which skill or ability the other combatant resists with",
+				},
+				callback: commandContest,
+			},
 			"roll": {
 				name:        "roll",
-				example:     "roll 2d4+2",
-				description: "Rolls the provided dice expression (such as d20, 8d6, or 2d4+2) and displays the total",
+				example:     "roll 2d6+1d4+3",
+				description: "Rolls the provided dice expression (such as d20, 8d6, 2d4+2, (1d8+2)*2, or 4d6kh3) and displays the total\n      along with every die that was rolled",
 				flags: map[string]string{
-					"--adv": "tells the battler to roll with advantage",
-					"--dis": "tells the battler to roll with disadvantage",
+					"--adv": "tells the battler to roll the whole expression twice and keep the higher total (advantage). For\n   advantage on each individual die, use a keep modifier instead, like 2d20kh1",
+					"--dis": "tells the battler to roll the whole expression twice and keep the lower total (disadvantage)",
 				},
 				callback: commandRoll,
 			},
+			"macro": {
+				name:        "macro",
+				example:     "macro gwm = 2d6+5+10",
+				description: "Saves the provided dice expression under the provided name, so it can be rolled with 'roll gwm'\n      or used inside other dice expressions as '$gwm'. Displays every macro if no name is provided",
+				flags: map[string]string{
+					"--delete": "tells the battler to delete the macro with the provided name instead",
+				},
+				callback: commandMacro,
+			},
+			"stats": {
+				name:        "stats",
+				example:     "stats 1d20+7 --adv, 18",
+				description: "Displays the min, max, mean, standard deviation, and full probability distribution of the\n      provided dice expression, and the chance of rolling at least the second argument if one is provided",
+				flags: map[string]string{
+					"--adv": "tells the battler to work out the stats for rolling with advantage",
+					"--dis": "tells the battler to work out the stats for rolling with disadvantage",
+				},
+				callback: commandStats,
+			},
+			"seed": {
+				name:        "seed",
+				example:     "seed 1234",
+				description: "Reseeds the dice with the provided number so the rolls that follow can be replayed later,\n      or displays the current seed if no number is provided",
+				callback:    commandSeed,
+			},
 			"view": {
 				name:        "view",
 				example:     "view",
@@ -105,18 +177,106 @@ func init() {
 			},
 			"action": {
 				name:        "action",
-				example:     "action tail whip --bonus",
-				description: "Takes the provided action of the selected combatant",
+				example:     "action tail whip --bonus, cabby the caterpie",
+				description: "Takes the provided action of the selected combatant. With a target, the attack roll, effects, and\n      saving throw get resolved against it, otherwise they're just rolled. A multiattack can take a\n      target for each of its attacks, in order, and the last target gets any attacks left over",
 				flags: map[string]string{
-					"--bonus": "tells the battler this is a bonus action",
-					"--re":    "tells the battler this is a reaction",
+					"--bonus":     "tells the battler this is a bonus action",
+					"--re":        "tells the battler this is a reaction",
+					"--legendary": "tells the battler this is a legendary action, which comes out of the combatant's legendary actions for the round",
+					"--lair":      "tells the battler this is a lair action",
+					"--force":     "tells the battler to take the action even if it's out of uses, hasn't recharged, or costs more legendary actions than are left",
 				},
 				callback: commandAction,
 			},
+			"init": {
+				name:        "init",
+				example:     "init all, blabby the blastoise --adv, alice --roll 17",
+				description: "Rolls initiative for the provided combatants (or every combatant with 'all' or no arguments),\n      sorts them into a turn order, starts round 1, and selects whoever goes first",
+				flags: map[string]string{
+					"--roll": "tells the battler to use the following initiative instead of rolling it, for player characters\n   who roll their own (they don't have to be combatants in the battler)",
+					"--adv":  "tells the battler to roll initiative with advantage",
+					"--dis":  "tells the battler to roll initiative with disadvantage",
+					"--join": "tells the battler to add the combatant to the fight already going instead of starting a new one",
+				},
+				callback: commandInit,
+			},
+			"next": {
+				name:        "next",
+				example:     "next",
+				description: "Moves on to the next turn in the initiative order and selects whoever's turn it is",
+				callback:    commandNext,
+			},
+			"prev": {
+				name:        "prev",
+				example:     "prev",
+				description: "Goes back to the previous turn in the initiative order and selects whoever's turn it was",
+				callback:    commandPrev,
+			},
+			"round": {
+				name:        "round",
+				example:     "round",
+				description: "Displays the current round and the initiative order",
+				callback:    commandRound,
+			},
+			"apply": {
+				name:        "apply",
+				example:     "apply frightened --rounds 10 --source blabby the blastoise, prone",
+				description: "Applies the provided condition(s) to the selected combatant. Conditions the battler knows\n      about (blinded, frightened, paralyzed, prone, restrained, etc.) automatically give advantage,\n      disadvantage, or automatic failures to the rolls they affect",
+				flags: map[string]string{
+					"--rounds":    "tells the battler the condition ends after the following number of the combatant's turns",
+					"--source":    "tells the battler who caused the condition",
+					"--next-turn": "tells the battler the condition ends at the end of the source's next turn. Without the source\n   flag, the source is whoever's turn it is",
+					"--conc":      "tells the battler the condition ends as soon as the source stops concentrating on their spell",
+				},
+				callback: commandApply,
+			},
+			"deathsave": {
+				name:        "deathsave",
+				example:     "deathsave",
+				description: "Rolls a death saving throw for the selected combatant if it's a dying player character, or records\n      the provided roll if they rolled it themselves",
+				flags: map[string]string{
+					"--stabilize": "tells the battler to stabilize the selected combatant instead, like Spare the Dying does",
+				},
+				callback: commandDeathSave,
+			},
+			"rest": {
+				name:        "rest",
+				example:     "rest short",
+				description: "Has the selected combatant take a short or long rest, giving back the uses of any actions that\n      come back after it, and half its hit dice after a long rest",
+				flags: map[string]string{
+					"--all":   "tells the battler every combatant takes the rest, not just the selected one",
+					"--spend": "tells the battler how many hit dice to spend healing during a short rest, like 'rest short --spend 2'",
+				},
+				callback: commandRest,
+			},
+			"concentrate": {
+				name:        "concentrate",
+				example:     "concentrate hold person",
+				description: "Has the selected combatant start concentrating on the provided spell, or displays what it's\n      concentrating on if no spell is provided. Damage automatically makes it roll concentration saves",
+				flags: map[string]string{
+					"--drop": "tells the battler the selected combatant stops concentrating instead",
+				},
+				callback: commandConcentrate,
+			},
+			"remove": {
+				name:        "remove",
+				example:     "remove prone",
+				description: "Removes the provided condition(s) from the selected combatant",
+				callback:    commandRemove,
+			},
+			"tag": {
+				name:        "tag",
+				example:     "tag goblins, goblin 1, @goblin",
+				description: "Tags the provided combatants (or the selected one) so they can all be targeted at once with\n      @goblins, or lists every tag with no arguments. Spawned copies are already in a group\n      named after the combatant they were spawned from, and @all,\n      @party (player characters), and @all-enemies are built in",
+				flags: map[string]string{
+					"--remove": "tells the battler to take the tag away instead",
+				},
+				callback: commandTag,
+			},
 			"cast": {
 				name:        "cast",
 				example:     "cast fireball --dc dc1 30 --am am1 19 --em em1 10, blabby the blastoise --dosav 1 1 dis --doatk 1 2 adv --do 1 3",
-				description: "Casts the provided spell on the provided target(s)",
+				description: "Casts the provided spell on the provided target(s), which can be groups like @goblins (see the tag\n      command) or @all, where every member of the group gets the group's flags",
 				flags: map[string]string{
 					"--dc":    "tells the battler that the following DC key (dc1 in the example) should be set to the following\n   value (30 in the example)",
 					"--am":    "this flag functions identically to the dc flag, but is used for attack modifiers instead\n   (+19 to hit in the example)",
@@ -132,14 +292,31 @@ func init() {
 			"help",
 			"exit",
 			"roll",
+			"macro",
+			"stats",
+			"seed",
 			"names",
 			"select",
 			"view",
+			"spawn",
+			"tag",
 			"dmg",
 			"heal",
+			"thp",
 			"action",
 			"save",
+			"check",
+			"contest",
 			"cast",
+			"apply",
+			"remove",
+			"concentrate",
+			"deathsave",
+			"rest",
+			"init",
+			"next",
+			"prev",
+			"round",
 		},
 		isRunning: true,
 	}
@@ -153,8 +330,23 @@ func init() {
 }
 
 func main() {
+	seed := flag.Int64("seed", time.Now().UnixNano(), "seeds the dice so an earlier session's rolls can be replayed")
+	flag.Parse()
+
+	cfg.seed = *seed
+	dice.SetRoller(dice.NewSeededRoller(cfg.seed))
+	fmt.Printf("Dice seed: %d (start with --seed %d to replay these rolls)\n", cfg.seed, cfg.seed)
+
 	var err error
 	scanner := bufio.NewScanner(os.Stdin)
+	combatant.SetPrompter(func(question string) bool {
+		fmt.Printf("%s [y/n] ", question)
+		if !scanner.Scan() {
+			return false
+		}
+		answer := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		return answer == "y" || answer == "yes"
+	})
 	for cfg.isRunning {
 		fmt.Print("D&DBattler > ")
 
@@ -182,6 +374,11 @@ func main() {
 		if err != nil {
 			fmt.Println(err)
 		}
+
+		// Whatever just happened might have broken someone's concentration
+		for _, ended := range cfg.battler.EndBrokenConcentration() {
+			fmt.Printf("%s is no longer %s\n", ended.Combatant, ended.Condition.Name)
+		}
 	}
 	err = process.SaveFiles(cfg.battler)
 	if err != nil {
@@ -189,6 +386,27 @@ func main() {
 	}
 }
 
+// splitArgs splits on commas, except for the ones listing the faces of a
+// custom die like 1d{1,1,2,2,3,4}
+func splitArgs(input string) []string {
+	var args []string
+	var depth, start int
+	for i, r := range input {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth = max(depth-1, 0)
+		case ',':
+			if depth == 0 {
+				args = append(args, input[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(args, input[start:])
+}
+
 func parseInput(input string) (command string, args []argument, err error) {
 	splitInput := strings.SplitN(strings.ToLower(input), " ", 2)
 	command = strings.TrimSpace(splitInput[0])
@@ -201,7 +419,7 @@ func parseInput(input string) (command string, args []argument, err error) {
 		return command, make([]argument, 1), nil
 	}
 
-	for rawArg := range strings.SplitSeq(splitInput[1], ",") {
+	for _, rawArg := range splitArgs(splitInput[1]) {
 		rawArg := strings.TrimLeft(rawArg, " ")
 
 		startOfFlagsIndex := strings.Index(rawArg, "--")
@@ -300,6 +518,101 @@ func commandSelect(cfg *config, params []argument) error {
 	return nil
 }
 
+func commandSpawn(cfg *config, params []argument) error {
+	if params[0].text == "" {
+		return fmt.Errorf("spawn takes the name of a combatant and how many copies to make, like 'spawn goblin 6'")
+	}
+
+	// The last word is how many, unless it's part of the name. Copies can't
+	// be spawned from, so 'goblin 6' means six goblins even once there's
+	// already a 'goblin 6'
+	template, count := params[0].text, 1
+	fields := strings.Fields(template)
+	var last int
+	_, err := fmt.Sscanf(fields[len(fields)-1], "%d", &last)
+	if len(fields) > 1 && err == nil {
+		prefix := strings.Join(fields[:len(fields)-1], " ")
+		c, prefixFound := cfg.battler.GetCombatant(prefix)
+		_, fullFound := cfg.battler.GetCombatant(template)
+		if (prefixFound && c.Template == "") || !fullFound {
+			template, count = prefix, last
+		}
+	}
+
+	hitPoints := ""
+	if _, rollPresent := params[0].flags["roll"]; rollPresent {
+		hitPoints = "roll"
+	} else if _, avgPresent := params[0].flags["avg"]; avgPresent {
+		hitPoints = "average"
+	}
+
+	spawned, err := cfg.battler.Spawn(template, count, hitPoints)
+	for _, s := range spawned {
+		if s.Rolled {
+			fmt.Printf("Spawned %s with %d HP (%s)\n", s.Name, s.HP, s.Roll)
+		} else {
+			fmt.Printf("Spawned %s with %d HP\n", s.Name, s.HP)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func commandTag(cfg *config, params []argument) error {
+	if params[0].text == "" {
+		tags := cfg.battler.Tags()
+		if len(tags) == 0 {
+			fmt.Println("Nobody has been tagged yet")
+			return nil
+		}
+		fmt.Println("Tags:")
+		for _, tag := range slices.Sorted(maps.Keys(tags)) {
+			fmt.Printf(" - @%s: %s\n", tag, strings.Join(tags[tag], ", "))
+		}
+		return nil
+	}
+
+	tag := strings.TrimPrefix(params[0].text, "@")
+	if slices.Contains(battler.Groups, tag) || strings.ContainsAny(tag, "@,") {
+		return fmt.Errorf("can't use '%s' as a tag", params[0].text)
+	}
+	_, removePresent := params[0].flags["remove"]
+
+	var tagging []*combatant.Combatant
+	if len(params) == 1 {
+		if cfg.selection.StatBlock.Name == "" {
+			return fmt.Errorf("tag requires a combatant to have already been selected using the select command, or combatants to tag")
+		}
+		tagging = append(tagging, cfg.selection)
+	} else {
+		targets, err := expandTargets(cfg, params[1:])
+		if err != nil {
+			return err
+		}
+		for _, target := range targets {
+			tagging = append(tagging, target.combatant)
+		}
+	}
+
+	for _, c := range tagging {
+		switch {
+		case removePresent && c.RemoveTag(tag):
+			fmt.Printf("%s is no longer in @%s\n", c.StatBlock.Name, tag)
+		case removePresent:
+			fmt.Printf("%s wasn't in @%s\n", c.StatBlock.Name, tag)
+		case c.AddTag(tag):
+			fmt.Printf("%s is now in @%s\n", c.StatBlock.Name, tag)
+		default:
+			fmt.Printf("%s is already in @%s\n", c.StatBlock.Name, tag)
+		}
+	}
+
+	return nil
+}
+
 func commandView(cfg *config, params []argument) error {
 	if cfg.selection.StatBlock.Name == "" {
 		return fmt.Errorf("view requires a combatant to have already been selected using the select command")
@@ -314,38 +627,71 @@ func commandDmg(cfg *config, params []argument) error {
 		return fmt.Errorf("dmg requires two arguments: the amount of damage, and the type of damage")
 	}
 
-	if cfg.selection.StatBlock.Name == "" {
-		return fmt.Errorf("dmg requires a combatant to have already been selected using the select command")
-	}
-
 	var dmg int
 	_, err := fmt.Sscanf(params[0].text, "%d", &dmg)
 	if err != nil {
-		return fmt.Errorf("dmg takes a whole number as it's first argument, not %s", params[0])
+		return fmt.Errorf("dmg takes a whole number as it's first argument, not %s", params[0].text)
 	}
 
-	report := cfg.selection.TakeDMG(dmg, params[1].text)
+	_, critOnAmount := params[0].flags["crit"]
+	_, critOnType := params[1].flags["crit"]
 
-	if report.WasAtZero {
-		fmt.Printf("%s was already at 0 hit points!\n", cfg.selection.StatBlock.Name)
+	if len(params) == 2 {
+		if cfg.selection.StatBlock.Name == "" {
+			return fmt.Errorf("dmg requires a combatant to have already been selected using the select command, or targets")
+		}
+		dealDMG(cfg.selection, dmg, params[1].text, critOnAmount || critOnType)
 		return nil
 	}
+
+	targets, err := expandTargets(cfg, params[2:])
+	if err != nil {
+		return err
+	}
+	before := hitPointsOf(targets)
+	for _, target := range targets {
+		_, critOnTarget := target.flags["crit"]
+		dealDMG(target.combatant, dmg, params[1].text, critOnAmount || critOnType || critOnTarget)
+	}
+	if len(targets) > 1 {
+		printTargetSummary(targets, before)
+	}
+
+	return nil
+}
+
+func dealDMG(c *combatant.Combatant, dmg int, dmgType string, critical bool) {
+	var report combatant.EffectReport
+	if critical {
+		report = c.TakeCritDMG(dmg, dmgType)
+	} else {
+		report = c.TakeDMG(dmg, dmgType)
+	}
+
+	if report.WasAtZero {
+		fmt.Printf("%s was already at 0 hit points!\n", c.StatBlock.Name)
+		printDeath(c, report)
+		return
+	}
 	if report.WasImmune {
-		fmt.Printf("%s is immune to %s damage!\n", cfg.selection.StatBlock.Name, params[1].text)
-		return nil
+		fmt.Printf("%s is immune to %s damage!\n", c.StatBlock.Name, dmgType)
+		return
 	}
 
 	if report.WasResistant {
-		fmt.Printf("%s is resistant to %s damage!\n", cfg.selection.StatBlock.Name, params[1].text)
+		fmt.Printf("%s is resistant to %s damage!\n", c.StatBlock.Name, dmgType)
+	}
+	if report.TempAbsorbed > 0 {
+		fmt.Printf("%s's temporary hit points absorbed %d damage!\n", c.StatBlock.Name, report.TempAbsorbed)
 	}
 	if report.WasVulnerable {
-		fmt.Printf("%s is vulnerable to %s damage!\n", cfg.selection.StatBlock.Name, params[1].text)
+		fmt.Printf("%s is vulnerable to %s damage!\n", c.StatBlock.Name, dmgType)
 	}
 	if report.DroppedToZero {
-		fmt.Printf("%s dropped to 0 hit points!\n", cfg.selection.StatBlock.Name)
+		fmt.Printf("%s dropped to 0 hit points!\n", c.StatBlock.Name)
 	}
-
-	return nil
+	printDeath(c, report)
+	printConcentration(c.StatBlock.Name, report)
 }
 
 func commandHeal(cfg *config, params []argument) error {
@@ -361,6 +707,9 @@ func commandHeal(cfg *config, params []argument) error {
 
 	report := cfg.selection.HealHP(hp)
 
+	if report.WasDead {
+		return fmt.Errorf("%s is dead, so healing won't do anything", cfg.selection.StatBlock.Name)
+	}
 	if report.BackAboveZero {
 		fmt.Printf("%s is back above 0 hit points!\n", cfg.selection.StatBlock.Name)
 	}
@@ -368,6 +717,28 @@ func commandHeal(cfg *config, params []argument) error {
 	return nil
 }
 
+func commandTHP(cfg *config, params []argument) error {
+	if cfg.selection.StatBlock.Name == "" {
+		return fmt.Errorf("thp requires a combatant to have already been selected using the select command")
+	}
+
+	var hp int
+	_, err := fmt.Sscanf(params[0].text, "%d", &hp)
+	if err != nil {
+		return fmt.Errorf("thp takes a whole number as an argument, not '%s'", params[0].text)
+	}
+
+	report := cfg.selection.GainTempHP(hp)
+
+	if report.KeptTemp {
+		fmt.Printf("%s keeps the %d temporary hit points it already had, since they don't stack!\n", cfg.selection.StatBlock.Name, report.TrueEffect)
+	} else {
+		fmt.Printf("%s has %d temporary hit points!\n", cfg.selection.StatBlock.Name, report.TrueEffect)
+	}
+
+	return nil
+}
+
 func commandAttack(cfg *config, params []argument) error {
 	if cfg.selection.StatBlock.Name == "" {
 		return fmt.Errorf("attack requires a combatant to have already been selected using the select command")
@@ -379,6 +750,10 @@ func commandAttack(cfg *config, params []argument) error {
 		return fmt.Errorf("attack takes a whole number as an argument, not '%s'", params[0].text)
 	}
 
+	for _, reason := range cfg.selection.DefenseModifiers().Reasons {
+		fmt.Printf("Attacks against %s have %s\n", cfg.selection.StatBlock.Name, reason)
+	}
+
 	if cfg.selection.Hits(attackRoll) {
 		fmt.Println("Hit!")
 	} else {
@@ -408,12 +783,71 @@ func commandSave(cfg *config, params []argument) error {
 	_, advPresent := params[1].flags["adv"]
 	_, disPresent := params[1].flags["dis"]
 
-	success, err := cfg.selection.Save(dc, ability, advPresent, disPresent)
+	save, err := cfg.selection.Save(dc, ability, advPresent, disPresent)
+	if err != nil {
+		return err
+	}
+
+	if !save.Modifiers.AutoFail {
+		fmt.Println(save.Roll)
+	}
+	for _, reason := range save.Modifiers.Reasons {
+		fmt.Printf("Rolled with %s\n", reason)
+	}
+
+	if save.LegendaryResistance {
+		printLegendaryResistance(cfg.selection)
+	}
+
+	if save.Success {
+		fmt.Println("Success!")
+	} else {
+		fmt.Println("Failure!")
+	}
+
+	return nil
+}
+
+func commandCheck(cfg *config, params []argument) error {
+	if cfg.selection.StatBlock.Name == "" {
+		return fmt.Errorf("check requires a combatant to have already been selected using the select command")
+	}
+
+	if params[0].text == "" {
+		return fmt.Errorf("check takes the name of a skill or an ability, like 'check stealth' or 'check str'")
+	}
+
+	_, advPresent := params[0].flags["adv"]
+	_, disPresent := params[0].flags["dis"]
+
+	// Left nil without a DC, since 0 is a DC too
+	var dc *int
+	if dcFlag, dcPresent := params[0].flags["dc"]; dcPresent {
+		if len(dcFlag) == 0 {
+			return fmt.Errorf("the dc flag takes a whole number, like 'check stealth --dc 15'")
+		}
+		value, err := strconv.Atoi(dcFlag[0])
+		if err != nil {
+			return fmt.Errorf("the dc flag takes a whole number, not '%s'", dcFlag[0])
+		}
+		dc = &value
+	}
+
+	skill := strings.ReplaceAll(params[0].text, " ", "_")
+	check, err := cfg.selection.Check(skill, dc, advPresent, disPresent)
 	if err != nil {
 		return err
 	}
 
-	if success {
+	fmt.Println(check.Roll)
+	for _, reason := range check.Modifiers.Reasons {
+		fmt.Printf("Rolled with %s\n", reason)
+	}
+
+	if dc == nil {
+		return nil
+	}
+	if check.Success {
 		fmt.Println("Success!")
 	} else {
 		fmt.Println("Failure!")
@@ -422,6 +856,70 @@ func commandSave(cfg *config, params []argument) error {
 	return nil
 }
 
+func commandContest(cfg *config, params []argument) error {
+	if cfg.selection.StatBlock.Name == "" {
+		return fmt.Errorf("contest requires a combatant to have already been selected using the select command")
+	}
+
+	if len(params) < 2 || params[0].text == "" {
+		return fmt.Errorf("contest requires two arguments: the skill or ability the selected combatant uses, and the\ncombatant it's up against, like 'contest athletics, goblin 3'")
+	}
+
+	other, ok := cfg.battler.GetCombatant(params[1].text)
+	if !ok {
+		return fmt.Errorf("could not find combatant: %s", params[1].text)
+	}
+
+	first := combatant.Contestant{
+		Combatant: cfg.selection,
+		Skill:     strings.ReplaceAll(params[0].text, " ", "_"),
+	}
+	_, first.Advantage = params[0].flags["adv"]
+	_, first.Disadvantage = params[0].flags["dis"]
+
+	second := combatant.Contestant{Combatant: other, Skill: first.Skill}
+	if first.Skill == "athletics" {
+		second.Skill = other.ResistWith()
+	}
+	if skill, skillPresent := params[1].flags["skill"]; skillPresent {
+		if len(skill) == 0 {
+			return fmt.Errorf("the skill flag takes the name of a skill or an ability, like '--skill acrobatics'")
+		}
+		second.Skill = strings.Join(skill, "_")
+	}
+	_, second.Advantage = params[1].flags["adv"]
+	_, second.Disadvantage = params[1].flags["dis"]
+
+	report, err := combatant.Contest(first, second)
+	if err != nil {
+		return err
+	}
+
+	for i, contestant := range []combatant.Contestant{first, second} {
+		check := report.Checks[i]
+		fmt.Printf(
+			"%s (%s): %s\n",
+			contestant.Combatant.StatBlock.Name,
+			strings.ReplaceAll(contestant.Skill, "_", " "),
+			check.Roll,
+		)
+		for _, reason := range check.Modifiers.Reasons {
+			fmt.Printf(" - Rolled with %s\n", reason)
+		}
+	}
+
+	switch report.Winner {
+	case 0:
+		fmt.Printf("%s wins!\n", first.Combatant.StatBlock.Name)
+	case 1:
+		fmt.Printf("%s wins!\n", second.Combatant.StatBlock.Name)
+	default:
+		fmt.Println("It's a tie, so nothing changes!")
+	}
+
+	return nil
+}
+
 func commandAction(cfg *config, params []argument) error {
 	if cfg.selection.StatBlock.Name == "" {
 		return fmt.Errorf("action requires a combatant to have already been selected using the select command")
@@ -438,6 +936,10 @@ func commandAction(cfg *config, params []argument) error {
 			actionType = "bonus action"
 		case "re":
 			actionType = "reaction"
+		case "legendary":
+			actionType = "legendary action"
+		case "lair":
+			actionType = "lair action"
 		}
 
 		if actionType != "action" {
@@ -445,12 +947,31 @@ func commandAction(cfg *config, params []argument) error {
 		}
 	}
 
+	_, forcePresent := params[0].flags["force"]
+
+	var targets []*combatant.Combatant
+	for _, param := range params[1:] {
+		c, ok := cfg.battler.GetCombatant(param.text)
+		if !ok {
+			return fmt.Errorf("could not find combatant: %s", param.text)
+		}
+		targets = append(targets, c)
+	}
+
 	actionName := strings.ReplaceAll(params[0].text, " ", "_")
-	err := cfg.selection.DoAction(actionName, actionType)
+	_, err := cfg.selection.DoAction(actionName, actionType, forcePresent, targets)
 	if err != nil {
 		return err
 	}
 
+	if actionType == "legendary action" {
+		fmt.Printf(
+			"%s has %d legendary actions left this round\n",
+			cfg.selection.StatBlock.Name,
+			cfg.selection.LegendaryActionsLeft(),
+		)
+	}
+
 	return nil
 }
 
@@ -468,6 +989,121 @@ func commandRoll(cfg *config, params []argument) error {
 	return nil
 }
 
+func commandMacro(cfg *config, params []argument) error {
+	if params[0].text == "" {
+		macros := dice.Macros()
+		if len(macros) == 0 {
+			fmt.Println("No macros have been saved yet")
+			return nil
+		}
+
+		fmt.Println("Macros:")
+		for _, name := range slices.Sorted(maps.Keys(macros)) {
+			fmt.Printf(" - %s = %s\n", name, macros[name])
+		}
+		return nil
+	}
+
+	if _, deletePresent := params[0].flags["delete"]; deletePresent {
+		err := dice.DeleteMacro(params[0].text)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Deleted macro '%s'\n", params[0].text)
+		return nil
+	}
+
+	name, expr, found := strings.Cut(params[0].text, "=")
+	if !found {
+		return fmt.Errorf("macro takes a name and a dice expression separated by '=', like 'macro gwm = 2d6+5+10'")
+	}
+
+	err := dice.SetMacro(name, expr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Saved macro '%s' as %s\n", strings.TrimSpace(name), strings.TrimSpace(expr))
+
+	return nil
+}
+
+func commandStats(cfg *config, params []argument) error {
+	d, err := dice.ReadDiceExpression(params[0].text)
+	if err != nil {
+		return err
+	}
+
+	_, advPresent := params[0].flags["adv"]
+	_, disPresent := params[0].flags["dis"]
+
+	distribution, err := d.Distribution(advPresent, disPresent)
+	if err != nil {
+		return fmt.Errorf("could not work out the stats for '%s': %s", d, err)
+	}
+
+	sep := "-------------------------------------------------------"
+	fmt.Println(sep)
+	fmt.Printf("%s\n", d)
+	fmt.Printf(" - Min: %d\n", distribution.Min())
+	fmt.Printf(" - Max: %d\n", distribution.Max())
+	fmt.Printf(" - Mean: %.2f\n", distribution.Mean())
+	fmt.Printf(" - Standard deviation: %.2f\n", distribution.StdDev())
+
+	if len(params) > 1 && params[1].text != "" {
+		var target int
+		_, err := fmt.Sscanf(params[1].text, "%d", &target)
+		if err != nil {
+			return fmt.Errorf("stats takes a whole number as it's second argument, not '%s'", params[1].text)
+		}
+		fmt.Printf(" - Chance of %d or higher: %.2f%%\n", target, distribution.AtLeast(target)*100)
+	}
+
+	fmt.Println(sep)
+
+	var highest float64
+	for _, p := range distribution {
+		highest = max(highest, p)
+	}
+
+	hidden := 0
+	for _, outcome := range distribution.Outcomes() {
+		p := distribution[outcome]
+		// Leave out the really unlikely totals, like the long tail of
+		// exploding dice
+		if p < 0.0001 {
+			hidden++
+			continue
+		}
+		fmt.Printf("%5d | %6.2f%% | %s\n", outcome, p*100, strings.Repeat("#", int(p/highest*40+0.5)))
+	}
+	if hidden != 0 {
+		fmt.Printf("(%d totals with less than a 0.01%% chance not shown)\n", hidden)
+	}
+
+	fmt.Println(sep)
+
+	return nil
+}
+
+func commandSeed(cfg *config, params []argument) error {
+	if params[0].text == "" {
+		fmt.Printf("Dice seed: %d\n", cfg.seed)
+		return nil
+	}
+
+	var seed int64
+	_, err := fmt.Sscanf(params[0].text, "%d", &seed)
+	if err != nil {
+		return fmt.Errorf("seed takes a whole number as an argument, not '%s'", params[0].text)
+	}
+
+	cfg.seed = seed
+	dice.SetRoller(dice.NewSeededRoller(seed))
+	fmt.Printf("Dice reseeded with %d\n", seed)
+
+	return nil
+}
+
 func commandCast(cfg *config, params []argument) error {
 	if len(params) < 2 {
 		return fmt.Errorf("cast requires at least two arguments: the name of the spell to be cast, and the target(s)")
@@ -533,13 +1169,16 @@ func commandCast(cfg *config, params []argument) error {
 		SaveDCs:         saveDCs,
 	}
 
+	expanded, err := expandTargets(cfg, params[1:])
+	if err != nil {
+		return err
+	}
+
 	var targets []spellbook.SpellTarget
+	before := hitPointsOf(expanded)
 
-	for _, targetArgument := range params[1:] {
-		c, ok := cfg.battler.GetCombatant(targetArgument.text)
-		if !ok {
-			continue
-		}
+	for _, targetArgument := range expanded {
+		c := targetArgument.combatant
 
 		var doAtks []spellbook.DoEffect
 		var doSavs []spellbook.DoEffect
@@ -626,5 +1265,508 @@ func commandCast(cfg *config, params []argument) error {
 
 	spell.Cast(targets, spellFlags)
 
+	if len(targets) > 1 {
+		printTargetSummary(expanded, before)
+	}
+
+	return nil
+}
+
+// groupTarget is one combatant out of a target argument, which might have
+// been a whole group, along with the argument's flags.
+type groupTarget struct {
+	combatant *combatant.Combatant
+	flags     map[string][]string
+}
+
+// expandTargets turns target arguments into the combatants they mean,
+// giving every member of a group the group's flags. Anyone mentioned twice
+// only counts the first time.
+func expandTargets(cfg *config, params []argument) ([]groupTarget, error) {
+	var targets []groupTarget
+	seen := map[string]bool{}
+	for _, param := range params {
+		names, err := cfg.battler.Targets(param.text)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range names {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			c, _ := cfg.battler.GetCombatant(name)
+			targets = append(targets, groupTarget{combatant: c, flags: param.flags})
+		}
+	}
+	return targets, nil
+}
+
+type hitPoints struct {
+	current, temp int
+}
+
+func hitPointsOf(targets []groupTarget) map[string]hitPoints {
+	hp := make(map[string]hitPoints, len(targets))
+	for _, target := range targets {
+		c := target.combatant
+		hp[c.StatBlock.Name] = hitPoints{current: c.StatBlock.HP["current"], temp: c.StatBlock.HP["temp"]}
+	}
+	return hp
+}
+
+// printTargetSummary shows how every target's hit points changed, so a
+// fireball into a room full of goblins doesn't have to be read line by line.
+func printTargetSummary(targets []groupTarget, before map[string]hitPoints) {
+	sep := "-------------------------------------------------------"
+	fmt.Println(sep)
+	fmt.Printf("%-28s %-14s %s\n", "Target", "HP", "Change")
+	for _, target := range targets {
+		c := target.combatant
+		old := before[c.StatBlock.Name]
+		current, temp := c.StatBlock.HP["current"], c.StatBlock.HP["temp"]
+
+		hp := fmt.Sprintf("%d -> %d", old.current, current)
+		change := fmt.Sprintf("%+d", current-old.current)
+		if temp != old.temp {
+			change += fmt.Sprintf(" (%+d temp)", temp-old.temp)
+		}
+		switch {
+		case c.IsDead():
+			change += ", dead"
+		case c.IsDying():
+			change += ", dying"
+		case current == 0 && old.current > 0:
+			change += ", down"
+		}
+		fmt.Printf("%-28s %-14s %s\n", c.StatBlock.Name, hp, change)
+	}
+	fmt.Println(sep)
+}
+
+func commandInit(cfg *config, params []argument) error {
+	var starting, joining []battler.InitiativeEntry
+	for _, param := range params {
+		names := []string{param.text}
+		if param.text == "" || param.text == "all" {
+			names = cfg.battler.InPlay()
+		}
+
+		_, advPresent := param.flags["adv"]
+		_, disPresent := param.flags["dis"]
+		manualRoll, manualPresent := param.flags["roll"]
+		_, joinPresent := param.flags["join"]
+
+		for _, name := range names {
+			var entry battler.InitiativeEntry
+			if manualPresent {
+				var total int
+				if len(manualRoll) == 0 {
+					return fmt.Errorf("the roll flag takes a whole number, like 'alice --roll 17'")
+				}
+				_, err := fmt.Sscanf(manualRoll[0], "%d", &total)
+				if err != nil {
+					return fmt.Errorf("the roll flag takes a whole number, not '%s'", manualRoll[0])
+				}
+				entry = cfg.battler.ManualInitiative(name, total)
+				fmt.Printf("%s: %d\n", name, total)
+			} else {
+				var roll dice.RollResult
+				var err error
+				entry, roll, err = cfg.battler.RollInitiative(name, advPresent, disPresent)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("%s: %s\n", name, roll)
+			}
+
+			if joinPresent {
+				joining = append(joining, entry)
+			} else {
+				starting = append(starting, entry)
+			}
+		}
+	}
+
+	var turn battler.TurnReport
+	if len(starting) != 0 {
+		turn = cfg.battler.StartInitiative(starting)
+	}
+	for _, entry := range joining {
+		err := cfg.battler.JoinInitiative(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	printInitiative(cfg)
+	if len(starting) != 0 {
+		printTurn(cfg, turn)
+	}
+
+	return nil
+}
+
+func commandNext(cfg *config, params []argument) error {
+	report, err := cfg.battler.NextTurn()
+	if err != nil {
+		return err
+	}
+
+	for _, ended := range report.EndedConditions {
+		fmt.Printf("%s is no longer %s\n", ended.Combatant, ended.Condition.Name)
+	}
+
+	printTurn(cfg, report)
+
+	return nil
+}
+
+// printTurn selects whoever's turn just started, and shows what came back to
+// them at the start of it.
+func printTurn(cfg *config, report battler.TurnReport) {
+	selectTurn(cfg, report.Entry, report.Round)
+
+	if report.LegendaryActions != 0 {
+		fmt.Printf("%s got back %d legendary actions\n", report.Entry.Name, report.LegendaryActions)
+	}
+	for _, recharge := range report.Recharges {
+		if recharge.Recharged {
+			fmt.Printf("%s recharged! (%s)\n", recharge.Action, recharge.Roll)
+		} else {
+			fmt.Printf("%s didn't recharge (%s)\n", recharge.Action, recharge.Roll)
+		}
+	}
+}
+
+func commandPrev(cfg *config, params []argument) error {
+	entry, err := cfg.battler.PrevTurn()
+	if err != nil {
+		return err
+	}
+
+	selectTurn(cfg, entry, cfg.battler.GetInitiative().Round)
+	return nil
+}
+
+func commandRound(cfg *config, params []argument) error {
+	if cfg.battler.GetInitiative().Round == 0 {
+		return fmt.Errorf("initiative hasn't been rolled yet - start a fight with the init command")
+	}
+
+	printInitiative(cfg)
+	return nil
+}
+
+func printLegendaryResistance(c *combatant.Combatant) {
+	fmt.Printf(
+		"%s used a legendary resistance to succeed instead (%d left)\n",
+		c.StatBlock.Name,
+		c.LegendaryResistancesLeft(),
+	)
+}
+
+func printInitiative(cfg *config) {
+	initiative := cfg.battler.GetInitiative()
+
+	sep := "-------------------------------------------------------"
+	fmt.Println(sep)
+	fmt.Printf("Round %d\n", initiative.Round)
+	fmt.Println(sep)
+	for i, entry := range initiative.Entries {
+		marker := "  "
+		if i == initiative.Turn {
+			marker = "->"
+		}
+		fmt.Printf("%s %3d  %s\n", marker, entry.Total, entry.Name)
+	}
+	fmt.Println(sep)
+}
+
+// selectTurn selects whoever's turn it is, as long as they're one of the
+// battler's combatants. On a lair's turn, the lair's owner gets selected.
+func selectTurn(cfg *config, entry battler.InitiativeEntry, round int) {
+	fmt.Printf("Round %d: it's %s's turn!\n", round, entry.Name)
+
+	if entry.LairOf != "" {
+		c, ok := cfg.battler.GetCombatant(entry.LairOf)
+		if !ok {
+			return
+		}
+		cfg.selection = c
+		fmt.Printf("Selected %s - take a lair action with 'action <name> --lair':\n", entry.LairOf)
+		for _, name := range slices.Sorted(maps.Keys(c.StatBlock.LairActions)) {
+			fmt.Printf(" - %s\n", strings.ReplaceAll(name, "_", " "))
+		}
+		return
+	}
+
+	if !entry.InBattler {
+		return
+	}
+
+	c, ok := cfg.battler.GetCombatant(entry.Name)
+	if !ok {
+		return
+	}
+	cfg.selection = c
+	fmt.Printf("Selected %s\n", entry.Name)
+
+	if c.IsDying() {
+		fmt.Printf("%s is dying! Roll a death save with the deathsave command\n", entry.Name)
+	}
+}
+
+func commandApply(cfg *config, params []argument) error {
+	if cfg.selection.StatBlock.Name == "" {
+		return fmt.Errorf("apply requires a combatant to have already been selected using the select command")
+	}
+
+	if params[0].text == "" {
+		return fmt.Errorf("apply takes the name of a condition, like 'apply prone'")
+	}
+
+	for _, param := range params {
+		condition := combatant.Condition{Name: param.text}
+
+		if rounds, roundsPresent := param.flags["rounds"]; roundsPresent {
+			if len(rounds) == 0 {
+				return fmt.Errorf("the rounds flag takes a whole number, like 'apply prone --rounds 2'")
+			}
+			_, err := fmt.Sscanf(rounds[0], "%d", &condition.Rounds)
+			if err != nil || condition.Rounds < 1 {
+				return fmt.Errorf("the rounds flag takes a whole number above 0, not '%s'", rounds[0])
+			}
+		}
+
+		if source, sourcePresent := param.flags["source"]; sourcePresent {
+			condition.Source = strings.Join(source, " ")
+		}
+
+		if _, nextTurnPresent := param.flags["next-turn"]; nextTurnPresent {
+			initiative := cfg.battler.GetInitiative()
+			if condition.Source == "" {
+				if initiative.Round == 0 {
+					return fmt.Errorf("the next-turn flag needs either a source or initiative to have been rolled")
+				}
+				condition.Source = initiative.Entries[initiative.Turn].Name
+			}
+			condition.UntilSourceTurnEnds = true
+		}
+
+		if _, concPresent := param.flags["conc"]; concPresent {
+			source, ok := cfg.battler.GetCombatant(condition.Source)
+			if !ok || source.Concentration == "" {
+				return fmt.Errorf("the conc flag needs a source that's concentrating on a spell - try the concentrate command")
+			}
+			condition.Spell = source.Concentration
+		}
+
+		lostConcentration, err := cfg.selection.ApplyCondition(condition)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s is now %s\n", cfg.selection.StatBlock.Name, condition)
+		if !combatant.KnownCondition(condition.Name) {
+			fmt.Printf("The battler doesn't know what '%s' does, so it won't change any rolls\n", condition.Name)
+		}
+		if lostConcentration != "" {
+			fmt.Printf("%s lost concentration on %s!\n", cfg.selection.StatBlock.Name, lostConcentration)
+		}
+	}
+
+	return nil
+}
+
+func commandRemove(cfg *config, params []argument) error {
+	if cfg.selection.StatBlock.Name == "" {
+		return fmt.Errorf("remove requires a combatant to have already been selected using the select command")
+	}
+
+	for _, param := range params {
+		if !cfg.selection.RemoveCondition(param.text) {
+			return fmt.Errorf("%s isn't %s", cfg.selection.StatBlock.Name, param.text)
+		}
+		fmt.Printf("%s is no longer %s\n", cfg.selection.StatBlock.Name, param.text)
+	}
+
+	return nil
+}
+
+func commandConcentrate(cfg *config, params []argument) error {
+	if cfg.selection.StatBlock.Name == "" {
+		return fmt.Errorf("concentrate requires a combatant to have already been selected using the select command")
+	}
+
+	name := cfg.selection.StatBlock.Name
+
+	if _, dropPresent := params[0].flags["drop"]; dropPresent {
+		spell := cfg.selection.DropConcentration()
+		if spell == "" {
+			return fmt.Errorf("%s isn't concentrating on anything", name)
+		}
+		fmt.Printf("%s stopped concentrating on %s\n", name, spell)
+		return nil
+	}
+
+	if params[0].text == "" {
+		if cfg.selection.Concentration == "" {
+			fmt.Printf("%s isn't concentrating on anything\n", name)
+		} else {
+			fmt.Printf("%s is concentrating on %s\n", name, cfg.selection.Concentration)
+		}
+		return nil
+	}
+
+	previous := cfg.selection.Concentrate(params[0].text)
+	if previous != "" {
+		fmt.Printf("%s stopped concentrating on %s\n", name, previous)
+	}
+	fmt.Printf("%s is concentrating on %s\n", name, params[0].text)
+
+	return nil
+}
+
+func printConcentration(name string, report combatant.EffectReport) {
+	if report.ConcentrationSave != nil {
+		fmt.Printf("%s made a DC %d concentration save: %s\n", name, report.ConcentrationDC, report.ConcentrationSave.Roll)
+	}
+	if report.LostConcentration != "" {
+		fmt.Printf("%s lost concentration on %s!\n", name, report.LostConcentration)
+	} else if report.ConcentrationSave != nil {
+		fmt.Printf("%s kept concentrating!\n", name)
+	}
+}
+
+func commandDeathSave(cfg *config, params []argument) error {
+	if cfg.selection.StatBlock.Name == "" {
+		return fmt.Errorf("deathsave requires a combatant to have already been selected using the select command")
+	}
+
+	name := cfg.selection.StatBlock.Name
+
+	if _, stabilizePresent := params[0].flags["stabilize"]; stabilizePresent {
+		err := cfg.selection.Stabilize()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s is stable!\n", name)
+		return nil
+	}
+
+	if !cfg.selection.IsDying() {
+		return fmt.Errorf("%s isn't dying", name)
+	}
+
+	var report combatant.DeathSaveReport
+	if params[0].text == "" {
+		var err error
+		report, err = cfg.selection.RollDeathSave()
+		if err != nil {
+			return err
+		}
+		fmt.Println(report.Roll)
+	} else {
+		var natural int
+		_, err := fmt.Sscanf(params[0].text, "%d", &natural)
+		if err != nil || natural < 1 || natural > 20 {
+			return fmt.Errorf("deathsave takes the number the d20 landed on, not '%s'", params[0].text)
+		}
+		report = cfg.selection.RecordDeathSave(natural)
+	}
+
+	switch {
+	case report.Revived:
+		fmt.Printf("Natural 20! %s is back up with 1 hit point!\n", name)
+	case report.Died:
+		fmt.Printf("%s died!\n", name)
+	case report.Stable:
+		fmt.Printf("%s is stable!\n", name)
+	case report.Natural == 1:
+		fmt.Printf("Natural 1, that's two failures! %s is %s\n", name, cfg.selection.DeathSaves)
+	case report.Natural >= 10:
+		fmt.Printf("Success! %s is %s\n", name, cfg.selection.DeathSaves)
+	default:
+		fmt.Printf("Failure! %s is %s\n", name, cfg.selection.DeathSaves)
+	}
+
+	return nil
+}
+
+func printDeath(c *combatant.Combatant, report combatant.EffectReport) {
+	name := c.StatBlock.Name
+	switch {
+	case report.MassiveDamage:
+		fmt.Printf("%s took massive damage and died instantly!\n", name)
+	case report.StartedDying:
+		fmt.Printf("%s is unconscious and dying!\n", name)
+	case report.DeathSaveFailures > 0:
+		fmt.Printf("%s failed %d death save(s) from taking damage!\n", name, report.DeathSaveFailures)
+		if report.Died {
+			fmt.Printf("%s died!\n", name)
+		} else {
+			fmt.Printf("%s is %s\n", name, c.DeathSaves)
+		}
+	}
+}
+
+func commandRest(cfg *config, params []argument) error {
+	var long bool
+	switch params[0].text {
+	case "short":
+	case "long":
+		long = true
+	default:
+		return fmt.Errorf("rest takes either 'short' or 'long' as an argument, not '%s'", params[0].text)
+	}
+
+	var resting []*combatant.Combatant
+	if _, allPresent := params[0].flags["all"]; allPresent {
+		for _, name := range slices.Sorted(maps.Keys(cfg.battler.Combatants)) {
+			c, _ := cfg.battler.GetCombatant(name)
+			resting = append(resting, c)
+		}
+	} else {
+		if cfg.selection.StatBlock.Name == "" {
+			return fmt.Errorf("rest requires a combatant to have already been selected using the select command, or the all flag")
+		}
+		resting = append(resting, cfg.selection)
+	}
+
+	spending := 0
+	if spend, spendPresent := params[0].flags["spend"]; spendPresent {
+		if long {
+			return fmt.Errorf("hit dice can only be spent during a short rest")
+		}
+		if len(spend) == 0 {
+			return fmt.Errorf("the spend flag takes how many hit dice to spend, like 'rest short --spend 2'")
+		}
+		_, err := fmt.Sscanf(spend[0], "%d", &spending)
+		if err != nil || spending < 1 {
+			return fmt.Errorf("the spend flag takes how many hit dice to spend, not '%s'", spend[0])
+		}
+	}
+
+	for _, c := range resting {
+		for range spending {
+			before := c.StatBlock.HP["current"]
+			report, err := c.SpendHitDie()
+			if err != nil {
+				fmt.Println(err)
+				break
+			}
+			fmt.Printf("%s spent a hit die and healed %d (%s)\n", c.StatBlock.Name, c.StatBlock.HP["current"]-before, report.Roll)
+			if report.Healing.BackAboveZero {
+				fmt.Printf("%s is back above 0 hit points!\n", c.StatBlock.Name)
+			}
+		}
+
+		fmt.Printf("%s finished a %s rest\n", c.StatBlock.Name, params[0].text)
+		for _, action := range c.Rest(long) {
+			fmt.Printf(" - %s can be used again\n", action)
+		}
+	}
+
 	return nil
 }